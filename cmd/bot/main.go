@@ -2,12 +2,13 @@ package main
 
 import (
 	"database/sql"
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/joho/godotenv"
 
 	"klutco-lil-helper/internal/bot"
+	"klutco-lil-helper/internal/logging"
 	"klutco-lil-helper/internal/model"
 )
 
@@ -15,14 +16,19 @@ func main() {
 	// Load .env if present (local dev)
 	_ = godotenv.Load()
 
+	// LOG_LEVEL and LOG_FORMAT configure every subsystem's logger; the standard
+	// log package (used by discordgo) is routed through it as well.
+	logger := logging.FromEnv()
+	slog.SetDefault(logger)
+
 	token := os.Getenv("DISCORD_BOT_TOKEN")
 	if token == "" {
-		log.Fatalln("DISCORD_BOT_TOKEN is not set")
+		fatal(logger, "DISCORD_BOT_TOKEN is not set", nil)
 	}
 
 	appId := os.Getenv("DISCORD_APP_ID")
 	if appId == "" {
-		log.Fatalln("DISCORD_APP_ID is not set")
+		fatal(logger, "DISCORD_APP_ID is not set", nil)
 	}
 	// Open (or create) the sqlite database. DB_PATH env var can override the default.
 	dbPath := os.Getenv("DB_PATH")
@@ -33,17 +39,17 @@ func main() {
 
 	db, err := sql.Open("sqlite", dbPath+"?_busy_timeout=5000&_journal_mode=WAL")
 	if err != nil {
-		log.Fatalln("failed to open database:", err)
+		fatal(logger, "failed to open database", err)
 	}
 
 	// Verify we can connect/open the file
 	if err := db.Ping(); err != nil {
-		log.Fatalln("failed to ping database", err)
+		fatal(logger, "failed to ping database", err)
 	}
 
 	// Run migrations
 	if err := model.Migrate(db); err != nil {
-		log.Fatalln("failed to migrate database:", err)
+		fatal(logger, "failed to migrate database", err)
 	}
 	// Important: limit to 1 connection for sqlite to avoid writer/reader connection churn.
 	db.SetMaxOpenConns(1)
@@ -56,12 +62,22 @@ func main() {
 	_, _ = db.Exec("PRAGMA journal_mode = WAL;")
 	_, _ = db.Exec("PRAGMA synchronous = NORMAL;")
 
-	b, err := bot.New(token, appId, db)
+	b, err := bot.New(token, appId, db, logger)
 	if err != nil {
-		log.Fatalf("failed to create bot: %v\n", err)
+		fatal(logger, "failed to create bot", err)
 	}
 
 	if err := b.Start(); err != nil {
-		log.Fatalf("bot stopped with error: %v\n", err)
+		fatal(logger, "bot stopped with error", err)
+	}
+}
+
+// fatal logs msg at error level and exits.
+func fatal(logger *slog.Logger, msg string, err error) {
+	if err != nil {
+		logger.Error(msg, logging.Err(err))
+	} else {
+		logger.Error(msg)
 	}
+	os.Exit(1)
 }
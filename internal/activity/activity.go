@@ -0,0 +1,164 @@
+// Package activity works out when clan members were last active, from the clan log
+// and from boss poll reactions of their linked Discord accounts.
+package activity
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxEmbedDescriptionLen is Discord's limit on an embed description.
+const maxEmbedDescriptionLen = 4096
+
+// MemberActivity is a clan member's latest activity the bot knows of.
+type MemberActivity struct {
+	GameName         string
+	Rank             idleclans.Rank
+	LastClanLog      time.Time // zero when the member never appeared in the clan log
+	LastPollResponse time.Time // zero when unlinked or never reacted to a boss poll
+}
+
+// LastSeen returns the latest of the member's activities, or zero when none is known.
+func (m MemberActivity) LastSeen() time.Time {
+	if m.LastPollResponse.After(m.LastClanLog) {
+		return m.LastPollResponse
+	}
+	return m.LastClanLog
+}
+
+// Collect combines the clan's member list with each member's last clan log line and,
+// for linked members, their last boss poll reaction.
+func Collect(clan *idleclans.Clan, lastLog, lastPoll map[string]time.Time, linked map[string]string) []MemberActivity {
+	logByName := make(map[string]time.Time, len(lastLog))
+	for name, t := range lastLog {
+		logByName[strings.ToLower(name)] = t
+	}
+	linkedByName := make(map[string]string, len(linked))
+	for name, id := range linked {
+		linkedByName[strings.ToLower(name)] = id
+	}
+
+	members := make([]MemberActivity, 0, len(clan.Members))
+	for _, cm := range clan.Members {
+		key := strings.ToLower(cm.Name)
+		m := MemberActivity{GameName: cm.Name, Rank: cm.Rank, LastClanLog: logByName[key]}
+		if id, ok := linkedByName[key]; ok {
+			m.LastPollResponse = lastPoll[id]
+		}
+		members = append(members, m)
+	}
+	return members
+}
+
+// Inactive returns the members not seen since cutoff, least recently seen first.
+func Inactive(members []MemberActivity, cutoff time.Time) []MemberActivity {
+	var inactive []MemberActivity
+	for _, m := range members {
+		if m.LastSeen().Before(cutoff) {
+			inactive = append(inactive, m)
+		}
+	}
+	sort.SliceStable(inactive, func(i, j int) bool {
+		a, b := inactive[i].LastSeen(), inactive[j].LastSeen()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return strings.ToLower(inactive[i].GameName) < strings.ToLower(inactive[j].GameName)
+	})
+	return inactive
+}
+
+// Load fetches the clan's member list and everyone's latest activity.
+func Load(ctx context.Context, db *sql.DB, client *idleclans.Client, clanName string) ([]MemberActivity, error) {
+	clan, err := client.ClanInfo(ctx, clanName)
+	if err != nil {
+		return nil, fmt.Errorf("fetch clan info: %w", err)
+	}
+	lastLog, err := model.LastClanLogActivity(db)
+	if err != nil {
+		return nil, fmt.Errorf("load clan log activity: %w", err)
+	}
+	lastPoll, err := model.LastPollResponses(db)
+	if err != nil {
+		return nil, fmt.Errorf("load poll responses: %w", err)
+	}
+	linked, err := model.ListLinkedMembers(db)
+	if err != nil {
+		return nil, fmt.Errorf("load linked members: %w", err)
+	}
+	return Collect(clan, lastLog, lastPoll, linked), nil
+}
+
+// FormatEmbed lists inactive members with when and where they were last seen.
+func FormatEmbed(inactive []MemberActivity, days int, now time.Time) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("💤 Inactive for %d+ days", days),
+		Color: 0x808080, // Grey
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Activity: clan log lines and boss poll reactions of linked members",
+		},
+	}
+	if len(inactive) == 0 {
+		embed.Description = "Everyone has been active. 🎉"
+		embed.Color = 0x00FF00 // Green
+		return embed
+	}
+
+	var sb strings.Builder
+	for i, m := range inactive {
+		line := formatMember(m, now)
+		if sb.Len()+len(line)+1 > maxEmbedDescriptionLen-len("\n…and 000 more") {
+			fmt.Fprintf(&sb, "\n…and %d more", len(inactive)-i)
+			break
+		}
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(line)
+	}
+	embed.Description = sb.String()
+	embed.Footer.Text = fmt.Sprintf("%d member(s) · %s", len(inactive), embed.Footer.Text)
+	return embed
+}
+
+func formatMember(m MemberActivity, now time.Time) string {
+	line := "**" + m.GameName + "**"
+	if m.Rank != idleclans.RankMember {
+		line += " (" + m.Rank.String() + ")"
+	}
+
+	seen := m.LastSeen()
+	if seen.IsZero() {
+		return line + " — never seen"
+	}
+	line += fmt.Sprintf(" — last seen %s", formatDaysAgo(now.Sub(seen)))
+
+	var sources []string
+	if !m.LastClanLog.IsZero() {
+		sources = append(sources, "clan log <t:"+fmt.Sprint(m.LastClanLog.Unix())+":d>")
+	}
+	if !m.LastPollResponse.IsZero() {
+		sources = append(sources, "boss poll <t:"+fmt.Sprint(m.LastPollResponse.Unix())+":d>")
+	}
+	return line + " · " + strings.Join(sources, " · ")
+}
+
+func formatDaysAgo(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "yesterday"
+	}
+	return fmt.Sprintf("%d days ago", days)
+}
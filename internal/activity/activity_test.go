@@ -0,0 +1,62 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+)
+
+func TestCollectAndInactive(t *testing.T) {
+	now := time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC)
+	days := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	clan := &idleclans.Clan{Members: []idleclans.ClanMember{
+		{Name: "active", Rank: idleclans.RankMember},
+		{Name: "Poller", Rank: idleclans.RankOfficer},
+		{Name: "quiet", Rank: idleclans.RankMember},
+		{Name: "ghost", Rank: idleclans.RankMember},
+	}}
+	lastLog := map[string]time.Time{
+		"active": days(1),
+		"poller": days(40), // clan log names are matched case-insensitively
+		"quiet":  days(20),
+		"gone":   days(2), // left the clan, not listed
+	}
+	lastPoll := map[string]time.Time{"p1": days(3)}
+	linked := map[string]string{"Poller": "p1"}
+
+	members := Collect(clan, lastLog, lastPoll, linked)
+	if len(members) != 4 {
+		t.Fatalf("got %d members, want 4", len(members))
+	}
+	if got := members[1].LastSeen(); !got.Equal(days(3)) {
+		t.Errorf("poller last seen %s, want the poll reaction", got)
+	}
+
+	inactive := Inactive(members, days(14))
+	var names []string
+	for _, m := range inactive {
+		names = append(names, m.GameName)
+	}
+	if got := strings.Join(names, ","); got != "ghost,quiet" {
+		t.Errorf("inactive = %s, want ghost,quiet (never seen first)", got)
+	}
+
+	embed := FormatEmbed(inactive, 14, now)
+	if !strings.Contains(embed.Description, "**ghost** — never seen") ||
+		!strings.Contains(embed.Description, "**quiet** — last seen 20 days ago · clan log <t:") {
+		t.Errorf("description = %q", embed.Description)
+	}
+	if !strings.HasPrefix(embed.Footer.Text, "2 member(s)") {
+		t.Errorf("footer = %q", embed.Footer.Text)
+	}
+}
+
+func TestFormatEmbedEveryoneActive(t *testing.T) {
+	embed := FormatEmbed(nil, 7, time.Now())
+	if !strings.Contains(embed.Description, "Everyone has been active") {
+		t.Errorf("description = %q", embed.Description)
+	}
+}
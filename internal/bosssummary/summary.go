@@ -3,21 +3,26 @@ package bosssummary
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	"sort"
 	"strings"
 	"time"
 
+	"klutco-lil-helper/internal/logging"
 	"klutco-lil-helper/internal/model"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 // RegenerateSummary regenerates the boss summary in the given channel.
-func RegenerateSummary(s *discordgo.Session, db *sql.DB, summaryChannelID string) error {
+func RegenerateSummary(s *discordgo.Session, db *sql.DB, summaryChannelID string, logger *slog.Logger) error {
 	if s == nil || db == nil {
 		return fmt.Errorf("session or db is nil")
 	}
+	if logger == nil {
+		logger = logging.Discard()
+	}
+	logger = logger.With(logging.KeyChannel, summaryChannelID)
 
 	// Find the boss channel (where the daily/weekly polls are)
 	var bossChannelID string
@@ -57,7 +62,7 @@ func RegenerateSummary(s *discordgo.Session, db *sql.DB, summaryChannelID string
 	}
 	isFriday := time.Now().In(loc).Weekday() == time.Friday
 
-	content := buildSummaryContent(s, bossChannelID, dailyMsgID, weeklyMsgID, idToName, isFriday)
+	content := buildSummaryContent(s, bossChannelID, dailyMsgID, weeklyMsgID, idToName, isFriday, logger)
 
 	// Try to edit existing summary message if it exists
 	oldMsgID, _ := model.GetScheduledMessage(db, model.MessageTypeBossSummary, summaryChannelID)
@@ -65,7 +70,7 @@ func RegenerateSummary(s *discordgo.Session, db *sql.DB, summaryChannelID string
 		_, err := s.ChannelMessageEdit(summaryChannelID, oldMsgID, content)
 		if err != nil {
 			// Edit failed (message may have been deleted), fall back to delete+send
-			log.Printf("[bosssummary] failed to edit message, falling back to new send: %v", err)
+			logger.Warn("failed to edit message, falling back to new send", logging.KeyMessageID, oldMsgID, logging.Err(err))
 			_ = s.ChannelMessageDelete(summaryChannelID, oldMsgID)
 
 			m, err := s.ChannelMessageSend(summaryChannelID, content)
@@ -75,7 +80,7 @@ func RegenerateSummary(s *discordgo.Session, db *sql.DB, summaryChannelID string
 
 			// Store the new message ID
 			if err := model.UpsertScheduledMessage(db, model.MessageTypeBossSummary, summaryChannelID, m.ID); err != nil {
-				log.Printf("[bosssummary] failed to store summary message ID: %v", err)
+				logger.Error("failed to store summary message ID", logging.KeyMessageID, m.ID, logging.Err(err))
 			}
 		}
 		// Edit succeeded, message ID remains the same
@@ -90,7 +95,7 @@ func RegenerateSummary(s *discordgo.Session, db *sql.DB, summaryChannelID string
 
 	// Store the new message ID
 	if err := model.UpsertScheduledMessage(db, model.MessageTypeBossSummary, summaryChannelID, m.ID); err != nil {
-		log.Printf("[bosssummary] failed to store summary message ID: %v", err)
+		logger.Error("failed to store summary message ID", logging.KeyMessageID, m.ID, logging.Err(err))
 	}
 
 	return nil
@@ -113,6 +118,7 @@ func buildSummaryContent(
 	bossChannelID, dailyMsgID, weeklyMsgID string,
 	idToName map[string]string,
 	isFriday bool,
+	logger *slog.Logger,
 ) string {
 	// Calculate max boss name length for alignment
 	maxNameLen := 0
@@ -129,10 +135,10 @@ func buildSummaryContent(
 		var dailyUsers, weeklyUsers map[string]bool
 
 		if !boss.WeeklyOnly {
-			dailyUsers = fetchReactedUsers(s, bossChannelID, dailyMsgID, boss.Emoji)
-			weeklyUsers = fetchReactedUsers(s, bossChannelID, weeklyMsgID, boss.Emoji)
+			dailyUsers = fetchReactedUsers(s, bossChannelID, dailyMsgID, boss.Emoji, logger)
+			weeklyUsers = fetchReactedUsers(s, bossChannelID, weeklyMsgID, boss.Emoji, logger)
 		} else {
-			weeklyUsers = fetchReactedUsers(s, bossChannelID, weeklyMsgID, boss.Emoji)
+			weeklyUsers = fetchReactedUsers(s, bossChannelID, weeklyMsgID, boss.Emoji, logger)
 		}
 
 		names := mergeReactionsToNames(dailyUsers, weeklyUsers, idToName, boss.WeeklyOnly)
@@ -158,14 +164,14 @@ func buildSummaryContent(
 }
 
 // fetchReactedUsers returns a set of non-bot user IDs that reacted with the given emoji.
-func fetchReactedUsers(s *discordgo.Session, channelID, messageID, emoji string) map[string]bool {
+func fetchReactedUsers(s *discordgo.Session, channelID, messageID, emoji string, logger *slog.Logger) map[string]bool {
 	if messageID == "" {
 		return nil
 	}
 
 	users, err := s.MessageReactions(channelID, messageID, emoji, 100, "", "")
 	if err != nil {
-		log.Printf("[bosssummary] failed to fetch reactions for %s on message %s: %v", emoji, messageID, err)
+		logger.Warn("failed to fetch reactions", "emoji", emoji, logging.KeyChannel, channelID, logging.KeyMessageID, messageID, logging.Err(err))
 		return nil
 	}
 
@@ -246,3 +252,18 @@ var summaryBosses = []bossEntry{
 	{"🐍", "Medusa", false},
 	{"💎", "Gem Quest", true},
 }
+
+// Boss is a boss of the polls and the emoji members react with to join it.
+type Boss struct {
+	Emoji string
+	Name  string
+}
+
+// Bosses returns the polled bosses in summary order.
+func Bosses() []Boss {
+	bosses := make([]Boss, len(summaryBosses))
+	for i, b := range summaryBosses {
+		bosses[i] = Boss{Emoji: b.Emoji, Name: b.Name}
+	}
+	return bosses
+}
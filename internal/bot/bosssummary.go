@@ -2,10 +2,11 @@ package bot
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"klutco-lil-helper/internal/bosssummary"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
 )
 
 // nextEasternTime returns the next occurrence of the specified time in America/New_York timezone.
@@ -25,12 +26,22 @@ func nextEasternTime(now time.Time, hour, minute int) time.Time {
 	return target
 }
 
+// nextEasternWeekday returns the next occurrence of the specified weekday and time in America/New_York.
+func nextEasternWeekday(now time.Time, day time.Weekday, hour, minute int) time.Time {
+	next := nextEasternTime(now, hour, minute)
+	for next.Weekday() != day {
+		next = nextEasternTime(next, hour, minute)
+	}
+	return next
+}
+
 // runBossSummary posts a boss fight summary every day at the specified time (Eastern).
 func (b *Bot) runBossSummary(ctx context.Context, summaryChannel, bossChannel string, hour, minute int) {
+	logger := b.logFor("bosssummary")
 	for {
 		next := nextEasternTime(time.Now(), hour, minute)
 		wait := time.Until(next)
-		log.Printf("[bosssummary] next summary at %s (in %s)", next.Format(time.RFC3339), wait)
+		logger.Info("next summary scheduled", "at", next.Format(time.RFC3339), "in", wait)
 
 		timer := time.NewTimer(wait)
 		select {
@@ -38,29 +49,32 @@ func (b *Bot) runBossSummary(ctx context.Context, summaryChannel, bossChannel st
 			if !timer.Stop() {
 				// drained or expired
 			}
-			log.Println("[bosssummary] context cancelled, stopping")
+			logger.Info("context cancelled, stopping")
 			return
 		case <-timer.C:
 			// time to post
 		}
 
 		if err := b.postBossSummary(summaryChannel, bossChannel); err != nil {
-			log.Printf("[bosssummary] failed to post summary: %v", err)
+			logger.Error("failed to post summary", logging.Err(err))
 		}
 	}
 }
 
 // postBossSummary fetches reactions from the current daily/weekly polls and posts a summary.
 func (b *Bot) postBossSummary(summaryChannelName, bossChannelName string) error {
+	defer metrics.JobDuration.ObserveDuration("bosssummary", time.Now())
+
 	if b.session == nil || b.session.State == nil || b.db == nil {
 		return nil
 	}
 
+	logger := b.logFor("bosssummary")
 	summaryChannelID := b.findChannelIDByName(summaryChannelName)
 	if summaryChannelID == "" {
-		log.Printf("[bosssummary] channel not found: summary=%q", summaryChannelName)
+		logger.Warn("summary channel not found", "channel_name", summaryChannelName)
 		return nil
 	}
 
-	return bosssummary.RegenerateSummary(b.session, b.db, summaryChannelID)
+	return bosssummary.RegenerateSummary(b.session, b.db, summaryChannelID, logger)
 }
@@ -74,3 +74,29 @@ func TestNextEasternTime(t *testing.T) {
 		})
 	}
 }
+
+func TestNextEasternWeekday(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		wantDay int
+	}{
+		{"wednesday, next monday", time.Date(2025, 1, 15, 15, 0, 0, 0, time.UTC), 20},
+		{"monday before 9:00 AM Eastern, same day", time.Date(2025, 1, 20, 13, 0, 0, 0, time.UTC), 20},
+		{"monday after 9:00 AM Eastern, a week later", time.Date(2025, 1, 20, 15, 0, 0, 0, time.UTC), 27},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextEasternWeekday(tt.now, time.Monday, 9, 0).In(loc)
+			if got.Weekday() != time.Monday || got.Day() != tt.wantDay || got.Hour() != 9 {
+				t.Errorf("got %s, want Monday the %dth at 9:00", got, tt.wantDay)
+			}
+		})
+	}
+}
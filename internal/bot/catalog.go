@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+)
+
+// default values
+const (
+	defaultCatalogRefresh    = 24 * time.Hour
+	catalogRetryInitialDelay = 1 * time.Minute
+)
+
+// loadItemCatalog loads the item catalog served at ITEM_CATALOG_URL over the embedded one.
+// It runs before the commands are registered, since which commands are offered depends
+// on what the catalog holds, and reports whether the load succeeded.
+func (b *Bot) loadItemCatalog(ctx context.Context, url string) bool {
+	logger := b.logFor("market").With("url", url)
+	loadCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if err := market.LoadCatalog(loadCtx, url); err != nil {
+		logger.Warn("failed to load item catalog, keeping the current one", logging.Err(err))
+		return false
+	}
+	logger.Info("loaded item catalog", "items", len(market.DefaultCatalog().Items()))
+	return true
+}
+
+// runCatalogLoader reloads the item catalog every refresh after the startup load. A failed
+// load keeps the current catalog and is retried with backoff instead of waiting for the next
+// refresh, so a short outage at startup doesn't leave the bot on the embedded foods until it
+// restarts. Commands registered at startup are not revisited.
+func (b *Bot) runCatalogLoader(ctx context.Context, url string, refresh time.Duration, loaded bool) {
+	if refresh <= 0 {
+		refresh = defaultCatalogRefresh
+	}
+
+	retry := catalogRetryInitialDelay
+	wait := refresh
+	if !loaded {
+		wait = retry
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		if b.loadItemCatalog(ctx, url) {
+			wait, retry = refresh, catalogRetryInitialDelay
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		retry = min(retry*2, refresh)
+		wait = retry
+	}
+}
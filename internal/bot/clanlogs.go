@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
 
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
 	"klutco-lil-helper/internal/model"
 )
 
@@ -30,10 +32,11 @@ func (b *Bot) runClanLogFetcher(ctx context.Context, interval time.Duration, url
 	}
 
 	client := &http.Client{Timeout: 15 * time.Second}
+	logger := b.logFor("clanlogs").With("url", url)
 
 	// immediate fetch
-	if err := fetchAndStoreClanLogs(ctx, client, url, b.db); err != nil {
-		log.Printf("[clanlogs] initial fetch failed: %v", err)
+	if err := b.fetchClanLogs(ctx, client, url, logger); err != nil {
+		logger.Error("initial fetch failed", logging.Err(err))
 	}
 
 	ticker := time.NewTicker(interval)
@@ -42,20 +45,39 @@ func (b *Bot) runClanLogFetcher(ctx context.Context, interval time.Duration, url
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("[clanlogs] stopping clan log fetcher")
+			logger.Info("stopping clan log fetcher")
 			return
 		case <-ticker.C:
-			if err := fetchAndStoreClanLogs(ctx, client, url, b.db); err != nil {
-				log.Printf("[clanlogs] scheduled fetch failed: %v", err)
+			if err := b.fetchClanLogs(ctx, client, url, logger); err != nil {
+				logger.Error("scheduled fetch failed", logging.Err(err))
 			}
 		}
 	}
 }
 
+// fetchClanLogs runs one fetch and records its outcome for /metrics and /readyz.
+func (b *Bot) fetchClanLogs(ctx context.Context, client *http.Client, url string, logger *slog.Logger) error {
+	defer metrics.JobDuration.ObserveDuration("clanlogs", time.Now())
+
+	newMsgs, err := fetchAndStoreClanLogs(ctx, client, url, b.db, logger)
+	if err != nil {
+		metrics.ClanLogFetchErrors.Inc()
+		return err
+	}
+
+	now := time.Now()
+	b.lastClanLogFetch.Store(now.UnixNano())
+	metrics.ClanLogLastSuccess.Set(float64(now.Unix()))
+
+	b.handleRosterChanges(newMsgs, now)
+	return nil
+}
+
 // fetchAndStoreClanLogs performs a single fetch + parse + store operation.
-func fetchAndStoreClanLogs(ctx context.Context, client *http.Client, url string, db *sql.DB) error {
+// It returns the lines that were stored for the first time.
+func fetchAndStoreClanLogs(ctx context.Context, client *http.Client, url string, db *sql.DB, logger *slog.Logger) ([]model.ClanMessage, error) {
 	if url == "" {
-		return errors.New("empty url")
+		return nil, errors.New("empty url")
 	}
 
 	var lastErr error
@@ -65,16 +87,16 @@ func fetchAndStoreClanLogs(ctx context.Context, client *http.Client, url string,
 	for i := 0; i < attempts; i++ {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		resp, err := client.Do(req)
 		if err != nil {
 			lastErr = err
-			log.Printf("[clanlogs] fetch attempt %d failed: %v", i+1, err)
+			logger.Warn("fetch attempt failed", "attempt", i+1, logging.Err(err))
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
+				return nil, ctx.Err()
 			case <-time.After(backoff):
 				backoff *= 2
 			}
@@ -85,49 +107,59 @@ func fetchAndStoreClanLogs(ctx context.Context, client *http.Client, url string,
 		body, err := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 			lastErr = errors.New("non-2xx status: " + resp.Status)
-			log.Printf("[clanlogs] fetch attempt %d returned status %s", i+1, resp.Status)
+			logger.Warn("fetch attempt returned non-2xx status", "attempt", i+1, "status", resp.Status)
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
+				return nil, ctx.Err()
 			case <-time.After(backoff):
 				backoff *= 2
 			}
 			continue
 		}
 
-		msgs, err := parseRawClanMessages(body)
+		msgs, err := parseRawClanMessages(body, logger)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		inserted := 0
+		var newMsgs []model.ClanMessage
 		for _, m := range msgs {
-			if err := model.InsertClanMessage(db, m); err != nil {
-				log.Printf("[clanlogs] failed to insert message: %v", err)
+			isNew, err := model.InsertClanMessage(db, m)
+			if err != nil {
+				logger.Error("failed to insert message", logging.Err(err))
 				// continue on individual DB errors
 				continue
 			}
 			inserted++
+
+			// only react to lines we have not seen before
+			if isNew {
+				metrics.ClanMessagesInserted.Inc()
+				applyKeyMovement(db, m, logger)
+				newMsgs = append(newMsgs, m)
+			}
 		}
 
-		log.Printf("[clanlogs] fetched %d messages, attempted inserts: %d", len(msgs), inserted)
-		return nil
+		metrics.ClanMessagesFetched.Add(len(msgs))
+		logger.Debug("fetched clan messages", "fetched", len(msgs), "inserted", inserted)
+		return newMsgs, nil
 	}
 
 	if lastErr != nil {
-		return lastErr
+		return nil, lastErr
 	}
-	return errors.New("fetch failed")
+	return nil, errors.New("fetch failed")
 }
 
 // parseRawClanMessages decodes the API JSON into []model.ClanMessage.
 // The API is expected to return a JSON array of objects with keys that map to ClanMessage fields.
-func parseRawClanMessages(body []byte) ([]model.ClanMessage, error) {
+func parseRawClanMessages(body []byte, logger *slog.Logger) ([]model.ClanMessage, error) {
 	// Try unmarshalling into a generic slice of maps to be tolerant of field names
 	var raw []map[string]interface{}
 	if err := json.Unmarshal(body, &raw); err != nil {
@@ -172,19 +204,19 @@ func parseRawClanMessages(body []byte) ([]model.ClanMessage, error) {
 			if t, err := parseTimestamp(v); err == nil {
 				m.Timestamp = t
 			} else {
-				log.Printf("[clanlogs] warning: failed to parse timestamp for item: %v", err)
+				logger.Warn("failed to parse timestamp for item, skipping", logging.Err(err))
 				continue // skip this item
 			}
 		} else if v, ok := item["time"]; ok {
 			if t, err := parseTimestamp(v); err == nil {
 				m.Timestamp = t
 			} else {
-				log.Printf("[clanlogs] warning: failed to parse timestamp for item: %v", err)
+				logger.Warn("failed to parse timestamp for item, skipping", logging.Err(err))
 				continue
 			}
 		} else {
 			// missing timestamp -> skip
-			log.Printf("[clanlogs] warning: item missing timestamp, skipping")
+			logger.Warn("item missing timestamp, skipping")
 			continue
 		}
 
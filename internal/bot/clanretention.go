@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
+	"klutco-lil-helper/internal/model"
+)
+
+const (
+	defaultClanMessageRetention       = 90 * 24 * time.Hour
+	defaultClanMessageArchiveInterval = 24 * time.Hour
+	// minClanMessageRetention keeps the lines the digest, /clan and /inactive read from clan_messages.
+	minClanMessageRetention = 30 * 24 * time.Hour
+	clanMessageArchiveBatch = 1000
+)
+
+// runClanMessageArchiver archives sent clan log lines older than retention on startup and
+// then once every interval.
+func (b *Bot) runClanMessageArchiver(ctx context.Context, retention, interval time.Duration) {
+	logger := b.logFor("retention")
+	if retention < minClanMessageRetention {
+		logger.Warn("clan message retention too short, using minimum", "retention", retention, "minimum", minClanMessageRetention)
+		retention = minClanMessageRetention
+	}
+	if interval <= 0 {
+		interval = defaultClanMessageArchiveInterval
+	}
+
+	b.archiveClanMessages(retention)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("stopping clan message archiver")
+			return
+		case <-ticker.C:
+			b.archiveClanMessages(retention)
+		}
+	}
+}
+
+// archiveClanMessages moves sent lines older than retention into the compressed archive.
+func (b *Bot) archiveClanMessages(retention time.Duration) {
+	defer metrics.JobDuration.ObserveDuration("retention", time.Now())
+
+	logger := b.logFor("retention")
+	if b.db == nil {
+		logger.Warn("no db available")
+		return
+	}
+
+	before := time.Now().Add(-retention)
+	result, err := model.ArchiveClanMessages(b.db, before, clanMessageArchiveBatch)
+	// count what was archived even when a later batch failed
+	metrics.ClanMessagesArchived.Add(int(result.Messages))
+	if err != nil {
+		logger.Error("failed to archive clan messages", "archived", result.Messages, logging.Err(err))
+		return
+	}
+	if result.Messages > 0 {
+		logger.Info("archived clan messages", "messages", result.Messages, "batches", result.Batches,
+			"before", before.UTC().Format(time.RFC3339))
+	}
+}
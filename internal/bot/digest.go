@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"klutco-lil-helper/internal/digest"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
+)
+
+// runWeeklyDigest posts the digest of the previous week every Monday at the given Eastern time.
+func (b *Bot) runWeeklyDigest(ctx context.Context, channelName string, hour, minute int) {
+	logger := b.logFor("digest")
+	for {
+		next := nextEasternWeekday(time.Now(), time.Monday, hour, minute)
+		wait := time.Until(next)
+		logger.Info("next digest scheduled", "at", next.Format(time.RFC3339), "in", wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Info("context cancelled, stopping")
+			return
+		case <-timer.C:
+		}
+
+		if err := b.postWeeklyDigest(channelName); err != nil {
+			logger.Error("failed to post weekly digest", logging.Err(err))
+		}
+	}
+}
+
+// postWeeklyDigest posts a new digest to the digest channel; /digest regenerates it later.
+func (b *Bot) postWeeklyDigest(channelName string) error {
+	defer metrics.JobDuration.ObserveDuration("digest", time.Now())
+
+	if b.session == nil || b.session.State == nil || b.db == nil {
+		return nil
+	}
+
+	logger := b.logFor("digest")
+	channelID := b.findChannelIDByName(channelName)
+	if channelID == "" {
+		logger.Warn("channel not found", "channel_name", channelName)
+		return nil
+	}
+	return digest.Publish(b.session, b.db, channelID, time.Now(), false, logger)
+}
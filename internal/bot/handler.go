@@ -5,9 +5,15 @@ import (
 	"database/sql"
 	"fmt"
 	"klutco-lil-helper/internal/commands"
-	"log"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -19,23 +25,51 @@ import (
 type Bot struct {
 	session *discordgo.Session
 	db      *sql.DB
+	logger  *slog.Logger
+
+	lastClanLogFetch atomic.Int64 // unix nanos of the last successful clan log fetch
+	webhooks         relayWebhooks
+	catalogURL       string // ITEM_CATALOG_URL, empty to use the embedded catalog only
+	catalogLoaded    bool   // whether the startup load of catalogURL succeeded
 }
 
-func New(token string, appId string, db *sql.DB) (*Bot, error) {
+func New(token string, appId string, db *sql.DB, logger *slog.Logger) (*Bot, error) {
 	dg, err := discordgo.New("Bot " + token)
 	if err != nil {
 		return nil, err
 	}
 
-	dg.Identify.Intents = discordgo.MakeIntent(discordgo.IntentsGuilds | discordgo.IntentsGuildMessages)
+	dg.Identify.Intents = discordgo.MakeIntent(discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsGuildMessageReactions)
+
+	// count failed REST calls for /metrics
+	base := dg.Client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	dg.Client.Transport = metricsTransport{base: base}
 
-	b := &Bot{session: dg, db: db}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	b := &Bot{session: dg, db: db, logger: logger}
 
-	// Make DB available to command handlers
+	// Make DB and logger available to command handlers
 	commands.SetDB(db)
+	commands.SetLogger(logging.For(logger, "commands"))
+
+	// merge the served item catalog over the embedded one, which only names the foods
+	if b.catalogURL = os.Getenv("ITEM_CATALOG_URL"); b.catalogURL != "" {
+		b.catalogLoaded = b.loadItemCatalog(context.Background(), b.catalogURL)
+	} else {
+		b.logFor("market").Warn("ITEM_CATALOG_URL is not set; only the embedded foods have names in /price, /market-deals and the digest")
+	}
 
 	commands.RegisterCommands(dg, appId)
 
+	// boss poll reactions count as member activity for /inactive and as participation in the digest
+	dg.AddHandler(b.onPollReaction)
+	dg.AddHandler(b.onPollReactionRemove)
+
 	return b, nil
 }
 
@@ -43,11 +77,26 @@ func (b *Bot) Start() error {
 	if err := b.session.Open(); err != nil {
 		return err
 	}
-	log.Println("Bot is now running. Press CTRL-C to exit.")
+	b.logger.Info("bot is now running, press CTRL-C to exit")
 
-	// start clan log fetcher
+	// every background worker runs under the supervisor so shutdown can wait for it
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	sup := newSupervisor(b.logFor("supervisor"))
+
+	// start the optional health and metrics server (HTTP_ADDR, e.g. ":8080")
+	if addr := os.Getenv("HTTP_ADDR"); addr != "" {
+		maxAge := b.envDuration("READY_CLAN_LOG_MAX_AGE", defaultClanLogMaxAge)
+		sup.Go(ctx, "httpserver", func(ctx context.Context) { b.runHTTPServer(ctx, addr, maxAge) })
+	}
+
+	// keep the served item catalog current
+	if b.catalogURL != "" {
+		catalogRefresh := b.envDuration("ITEM_CATALOG_REFRESH", defaultCatalogRefresh)
+		sup.Go(ctx, "catalog", func(ctx context.Context) {
+			b.runCatalogLoader(ctx, b.catalogURL, catalogRefresh, b.catalogLoaded)
+		})
+	}
 
 	url := os.Getenv("CLAN_LOG_URL")
 	interval := 24 * time.Hour
@@ -56,21 +105,32 @@ func (b *Bot) Start() error {
 			interval = d
 		}
 	}
-	go b.runClanLogFetcher(ctx, interval, url)
+	sup.Go(ctx, "clanlogs", func(ctx context.Context) { b.runClanLogFetcher(ctx, interval, url) })
 
-	go b.runClanLogFetcher(ctx, 1*time.Minute, "https://query.idleclans.com/api/Clan/logs/clan/KlutzCo?limit=10")
+	sup.Go(ctx, "clanlogs-recent", func(ctx context.Context) {
+		b.runClanLogFetcher(ctx, 1*time.Minute, "https://query.idleclans.com/api/Clan/logs/clan/KlutzCo?limit=10")
+	})
 
-	// start message sender (every 30s)
-	channelName := os.Getenv("CLAN_MESSAGE_CHANNEL")
-	donationChannel := os.Getenv("CLAN_DONATION_CHANNEL")
-	go b.runMessageSender(ctx, channelName, donationChannel)
+	// start message sender (every 30s); RELAY_MODE=webhook posts lines under the player's name
+	relayCfg := relayConfig{
+		Channel:         os.Getenv("CLAN_MESSAGE_CHANNEL"),
+		DonationChannel: os.Getenv("CLAN_DONATION_CHANNEL"),
+		Webhook:         strings.EqualFold(os.Getenv("RELAY_MODE"), "webhook"),
+	}
+	sup.Go(ctx, "messagesender", func(ctx context.Context) { b.runMessageSender(ctx, relayCfg) })
+
+	// archive sent clan log lines older than CLAN_MESSAGE_RETENTION (e.g. "2160h"); 0 turns archiving off
+	if retention := b.envDuration("CLAN_MESSAGE_RETENTION", defaultClanMessageRetention); retention > 0 {
+		archiveInterval := b.envDuration("CLAN_MESSAGE_ARCHIVE_INTERVAL", defaultClanMessageArchiveInterval)
+		sup.Go(ctx, "retention", func(ctx context.Context) { b.runClanMessageArchiver(ctx, retention, archiveInterval) })
+	}
 
 	// start boss scheduler (posts to channel named by BOSS_CHANNEL, default "boss")
 	bossChannel := os.Getenv("BOSS_CHANNEL")
 	if bossChannel == "" {
 		bossChannel = "tactical-dispatch"
 	}
-	go b.runBossScheduler(ctx, bossChannel)
+	sup.Go(ctx, "messagescheduler", func(ctx context.Context) { b.runBossScheduler(ctx, bossChannel) })
 
 	// start boss summary (posts to channel named by BOSS_SUMMARY_CHANNEL, default "general")
 	bossSummaryChannel := os.Getenv("BOSS_SUMMARY_CHANNEL")
@@ -87,21 +147,129 @@ func (b *Bot) Start() error {
 	if _, err := time.Parse("15:04", bossSummaryTime); err == nil {
 		_, _ = fmt.Sscanf(bossSummaryTime, "%d:%d", &summaryHour, &summaryMinute)
 	} else {
-		log.Printf("[bosssummary] invalid BOSS_SUMMARY_TIME format %q, using default 9:30", bossSummaryTime)
+		b.logFor("bosssummary").Warn("invalid BOSS_SUMMARY_TIME format, using default 9:30", "value", bossSummaryTime)
 		summaryHour, summaryMinute = 9, 30
 	}
-	go b.runBossSummary(ctx, bossSummaryChannel, bossChannel, summaryHour, summaryMinute)
+	sup.Go(ctx, "bosssummary", func(ctx context.Context) {
+		b.runBossSummary(ctx, bossSummaryChannel, bossChannel, summaryHour, summaryMinute)
+	})
+
+	// keep the shared market price cache warm so commands rarely wait on the API
+	cacheTTL := b.envDuration("MARKET_CACHE_TTL", market.DefaultCacheTTL)
+	market.Prices.SetTTL(cacheTTL)
+	sup.Go(ctx, "marketcache", func(ctx context.Context) { market.Prices.Run(ctx, cacheTTL, b.logFor("market")) })
+
+	// start market price snapshots (durations like "1h", "168h")
+	snapshotCfg := marketSnapshotConfig{
+		Interval:       b.envDuration("MARKET_SNAPSHOT_INTERVAL", defaultMarketSnapshotInterval),
+		RawRetention:   b.envDuration("MARKET_RAW_RETENTION", defaultMarketRawRetention),
+		DailyRetention: b.envDuration("MARKET_DAILY_RETENTION", defaultMarketDailyRetention),
+		AlertCooldown:  b.envDuration("PRICE_ALERT_COOLDOWN", defaultPriceAlertCooldown),
+	}
+	sup.Go(ctx, "marketsnapshot", func(ctx context.Context) { b.runMarketSnapshotter(ctx, snapshotCfg) })
+
+	// start the optional daily market movers post (MARKET_MOVERS_CHANNEL, at MARKET_MOVERS_TIME Eastern)
+	if moversChannel := os.Getenv("MARKET_MOVERS_CHANNEL"); moversChannel != "" {
+		moversHour, moversMinute := b.envClock("MARKET_MOVERS_TIME", 9, 0)
+		sup.Go(ctx, "marketmovers", func(ctx context.Context) {
+			b.runMarketMovers(ctx, moversChannel, moversHour, moversMinute)
+		})
+	}
+
+	// mirror in-game ranks onto the roles mapped with /ranks (RANK_SYNC_INTERVAL=0 disables,
+	// RANK_SYNC_DRY_RUN=true only logs the changes)
+	if rankSyncInterval := b.envDuration("RANK_SYNC_INTERVAL", defaultRankSyncInterval); rankSyncInterval > 0 {
+		dryRun := strings.EqualFold(os.Getenv("RANK_SYNC_DRY_RUN"), "true")
+		sup.Go(ctx, "ranksync", func(ctx context.Context) { b.runRankSync(ctx, rankSyncInterval, dryRun) })
+	}
+
+	// snapshot linked members' skill XP daily (XP_SNAPSHOT_TIME Eastern) for /xp leaderboards
+	xpHour, xpMinute := b.envClock("XP_SNAPSHOT_TIME", 0, 5)
+	sup.Go(ctx, "xpsnapshot", func(ctx context.Context) { b.runXPSnapshots(ctx, xpHour, xpMinute) })
+
+	// start the optional weekly inactive member report (INACTIVE_REPORT_CHANNEL, Mondays at INACTIVE_REPORT_TIME Eastern)
+	if inactiveChannel := os.Getenv("INACTIVE_REPORT_CHANNEL"); inactiveChannel != "" {
+		inactiveDays := b.envInt("INACTIVE_REPORT_DAYS", defaultInactiveReportDays)
+		inactiveHour, inactiveMinute := b.envClock("INACTIVE_REPORT_TIME", 9, 0)
+		sup.Go(ctx, "inactivereport", func(ctx context.Context) {
+			b.runInactiveReport(ctx, inactiveChannel, inactiveDays, inactiveHour, inactiveMinute)
+		})
+	}
+
+	// start the optional weekly clan digest (DIGEST_CHANNEL, Mondays at DIGEST_TIME Eastern)
+	if digestChannel := os.Getenv("DIGEST_CHANNEL"); digestChannel != "" {
+		digestHour, digestMinute := b.envClock("DIGEST_TIME", 8, 0)
+		sup.Go(ctx, "digest", func(ctx context.Context) { b.runWeeklyDigest(ctx, digestChannel, digestHour, digestMinute) })
+	}
 
 	// Wait for interrupt signal to gracefully shut down
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	<-stop
 
-	log.Println("Shutting down bot...")
-	// Close discord session first, then DB
+	b.logger.Info("shutting down bot")
+
+	// closing the gateway stops event dispatch, so no new handler can write to the DB;
+	// REST calls still work, letting the workers finish in-flight sends and inserts
 	err := b.session.Close()
+	cancel()
+	timeout := b.envDuration("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+	if !sup.Wait(timeout) {
+		b.logger.Warn("background workers did not stop in time, closing anyway", "timeout", timeout)
+	}
+
 	if b.db != nil {
 		_ = b.db.Close()
 	}
 	return err
 }
+
+// logFor returns the logger for one of the bot's subsystems.
+func (b *Bot) logFor(subsystem string) *slog.Logger {
+	return logging.For(b.logger, subsystem)
+}
+
+// envDuration reads a time.Duration from the named environment variable,
+// falling back to def when unset or invalid.
+func (b *Bot) envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		b.logFor("config").Warn("invalid duration, using default", "name", name, "value", v, "default", def)
+		return def
+	}
+	return d
+}
+
+// envInt reads a positive integer from the named environment variable,
+// falling back to def when unset or invalid.
+func (b *Bot) envInt(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		b.logFor("config").Warn("invalid number, using default", "name", name, "value", v, "default", def)
+		return def
+	}
+	return n
+}
+
+// envClock reads an "HH:MM" time of day from the named environment variable,
+// falling back to defHour:defMinute when unset or invalid.
+func (b *Bot) envClock(name string, defHour, defMinute int) (int, int) {
+	v := os.Getenv(name)
+	if v == "" {
+		return defHour, defMinute
+	}
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		b.logFor("config").Warn("invalid time of day, using default", "name", name, "value", v, "default", fmt.Sprintf("%d:%02d", defHour, defMinute))
+		return defHour, defMinute
+	}
+	return t.Hour(), t.Minute()
+}
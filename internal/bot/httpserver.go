@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
+)
+
+// defaultClanLogMaxAge is how long /readyz tolerates no successful clan log fetch.
+const defaultClanLogMaxAge = 15 * time.Minute
+
+// runHTTPServer serves /healthz, /readyz and /metrics on addr until ctx is cancelled.
+func (b *Bot) runHTTPServer(ctx context.Context, addr string, clanLogMaxAge time.Duration) {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           b.httpHandler(clanLogMaxAge),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	// ListenAndServe returns as soon as Shutdown starts, so wait for in-flight requests here
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	logger := b.logFor("httpserver")
+	logger.Info("listening", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		// returning early while ctx is alive lets the supervisor retry the listener
+		logger.Error("server stopped", logging.Err(err))
+		return
+	}
+	<-stopped
+}
+
+// httpHandler routes the health and metrics endpoints.
+func (b *Bot) httpHandler(clanLogMaxAge time.Duration) http.Handler {
+	mux := http.NewServeMux()
+
+	// liveness: the process is up and serving requests
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+		defer cancel()
+
+		problems := b.readinessProblems(ctx, time.Now(), clanLogMaxAge)
+		if len(problems) > 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, strings.Join(problems, "\n"))
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	mux.Handle("/metrics", metrics.Default.Handler())
+	return mux
+}
+
+// readinessProblems lists every reason the bot is not ready; empty means ready.
+func (b *Bot) readinessProblems(ctx context.Context, now time.Time, clanLogMaxAge time.Duration) []string {
+	var problems []string
+
+	if !b.sessionOpen() {
+		problems = append(problems, "discord session not open")
+	}
+
+	if b.db == nil {
+		problems = append(problems, "no database")
+	} else if err := b.db.PingContext(ctx); err != nil {
+		problems = append(problems, "database unreachable: "+err.Error())
+	}
+
+	last := b.lastClanLogFetch.Load()
+	if last == 0 {
+		problems = append(problems, "no successful clan log fetch yet")
+	} else if age := now.Sub(time.Unix(0, last)); age > clanLogMaxAge {
+		problems = append(problems, "last successful clan log fetch "+age.Round(time.Second).String()+" ago")
+	}
+
+	return problems
+}
+
+// sessionOpen reports whether the Discord gateway connection is up.
+func (b *Bot) sessionOpen() bool {
+	if b.session == nil {
+		return false
+	}
+	b.session.RLock()
+	defer b.session.RUnlock()
+	return b.session.DataReady
+}
+
+// metricsTransport counts failed Discord REST calls.
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+func (t metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		metrics.DiscordAPIErrors.Inc("transport")
+		return resp, err
+	}
+	if resp.StatusCode >= 400 {
+		metrics.DiscordAPIErrors.Inc(strconv.Itoa(resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestReadinessProblems(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	b := &Bot{db: db}
+
+	problems := b.readinessProblems(context.Background(), now, 15*time.Minute)
+	if !containsProblem(problems, "discord session not open") || !containsProblem(problems, "no successful clan log fetch") {
+		t.Errorf("problems = %v, want session and clan log problems", problems)
+	}
+
+	b.lastClanLogFetch.Store(now.Add(-time.Hour).UnixNano())
+	problems = b.readinessProblems(context.Background(), now, 15*time.Minute)
+	if !containsProblem(problems, "last successful clan log fetch 1h0m0s ago") {
+		t.Errorf("problems = %v, want stale clan log fetch", problems)
+	}
+
+	b.lastClanLogFetch.Store(now.Add(-time.Minute).UnixNano())
+	problems = b.readinessProblems(context.Background(), now, 15*time.Minute)
+	if containsProblem(problems, "clan log") || containsProblem(problems, "database") {
+		t.Errorf("problems = %v, want only the session problem", problems)
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	b := &Bot{}
+	h := b.httpHandler(time.Minute)
+
+	for path, want := range map[string]int{
+		"/healthz": http.StatusOK,
+		"/readyz":  http.StatusServiceUnavailable,
+		"/metrics": http.StatusOK,
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
+func containsProblem(problems []string, substr string) bool {
+	for _, p := range problems {
+		if strings.Contains(p, substr) {
+			return true
+		}
+	}
+	return false
+}
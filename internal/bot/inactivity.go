@@ -0,0 +1,103 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"klutco-lil-helper/internal/activity"
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// defaultInactiveReportDays is the inactivity threshold of the weekly officer report.
+const defaultInactiveReportDays = 14
+
+// onPollReaction records boss poll reactions as member activity and as boss participation.
+func (b *Bot) onPollReaction(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	if b.db == nil || (s.State.User != nil && r.UserID == s.State.User.ID) {
+		return
+	}
+
+	logger := b.logFor("activity").With(logging.KeyMessageID, r.MessageID)
+	isPoll, err := model.IsBossPollMessage(b.db, r.MessageID)
+	if err != nil {
+		logger.Error("failed to check for boss poll", logging.Err(err))
+		return
+	}
+	if !isPoll {
+		return
+	}
+	now := time.Now()
+	if err := model.RecordPollResponse(b.db, r.UserID, now); err != nil {
+		logger.Error("failed to record poll response", "user", r.UserID, logging.Err(err))
+	}
+	if err := model.RecordBossPollReaction(b.db, r.MessageID, r.Emoji.Name, r.UserID, now); err != nil {
+		logger.Error("failed to record boss poll reaction", "user", r.UserID, "emoji", r.Emoji.Name, logging.Err(err))
+	}
+}
+
+// onPollReactionRemove forgets boss participation that was taken back. The member still
+// counts as active.
+func (b *Bot) onPollReactionRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
+	if b.db == nil || (s.State.User != nil && r.UserID == s.State.User.ID) {
+		return
+	}
+
+	if err := model.DeleteBossPollReaction(b.db, r.MessageID, r.Emoji.Name, r.UserID); err != nil {
+		b.logFor("activity").Error("failed to remove boss poll reaction",
+			logging.KeyMessageID, r.MessageID, "user", r.UserID, "emoji", r.Emoji.Name, logging.Err(err))
+	}
+}
+
+// runInactiveReport posts the inactive member list every Monday at the given Eastern time.
+func (b *Bot) runInactiveReport(ctx context.Context, channelName string, days, hour, minute int) {
+	logger := b.logFor("inactivity")
+	for {
+		next := nextEasternWeekday(time.Now(), time.Monday, hour, minute)
+		wait := time.Until(next)
+		logger.Info("next report scheduled", "at", next.Format(time.RFC3339), "in", wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Info("context cancelled, stopping")
+			return
+		case <-timer.C:
+		}
+
+		if err := b.postInactiveReport(ctx, channelName, days); err != nil {
+			logger.Error("failed to post inactive report", logging.Err(err))
+		}
+	}
+}
+
+// postInactiveReport posts members not seen for days to the officer channel.
+func (b *Bot) postInactiveReport(ctx context.Context, channelName string, days int) error {
+	defer metrics.JobDuration.ObserveDuration("inactivereport", time.Now())
+
+	if b.session == nil || b.session.State == nil || b.db == nil {
+		return nil
+	}
+
+	logger := b.logFor("inactivity")
+	channelID := b.findChannelIDByName(channelName)
+	if channelID == "" {
+		logger.Warn("channel not found", "channel_name", channelName)
+		return nil
+	}
+
+	members, err := activity.Load(ctx, b.db, idleclans.Default, model.ClanName)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	inactive := activity.Inactive(members, now.AddDate(0, 0, -days))
+
+	_, err = b.session.ChannelMessageSendEmbed(channelID, activity.FormatEmbed(inactive, days, now))
+	return err
+}
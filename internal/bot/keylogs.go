@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"database/sql"
+	"log/slog"
+	"strings"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+)
+
+// keyMovement describes keys moving between a member and the clan vault.
+type keyMovement struct {
+	Player  string
+	Key     string
+	Count   int
+	Deposit bool
+}
+
+// parseKeyMovement extracts a boss key deposit or withdrawal from a clan log line.
+func parseKeyMovement(message string) (keyMovement, bool) {
+	mv, ok := model.ParseVaultMovement(message)
+	if !ok || mv.IsGold() {
+		return keyMovement{}, false
+	}
+
+	key := strings.TrimSuffix(strings.ToLower(mv.Item), " key")
+	if _, ok := model.KeysInformation[key]; !ok {
+		return keyMovement{}, false
+	}
+
+	return keyMovement{
+		Player:  mv.Player,
+		Key:     key,
+		Count:   int(mv.Count),
+		Deposit: mv.Deposit,
+	}, true
+}
+
+// applyKeyMovement moves keys between the member and the vault in the key inventory.
+func applyKeyMovement(db *sql.DB, msg model.ClanMessage, logger *slog.Logger) {
+	mv, ok := parseKeyMovement(msg.Message)
+	if !ok {
+		return
+	}
+
+	memberDelta, vaultDelta := mv.Count, -mv.Count
+	if mv.Deposit {
+		memberDelta, vaultDelta = -mv.Count, mv.Count
+	}
+
+	if err := model.AdjustKeyCount(db, mv.Player, mv.Key, memberDelta); err != nil {
+		logger.Error("failed to adjust member keys", "key", mv.Key, "player", mv.Player, logging.Err(err))
+	}
+	if err := model.AdjustKeyCount(db, model.KeyVaultHolder, mv.Key, vaultDelta); err != nil {
+		logger.Error("failed to adjust vault keys", "key", mv.Key, logging.Err(err))
+	}
+}
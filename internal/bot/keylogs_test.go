@@ -0,0 +1,59 @@
+package bot
+
+import "testing"
+
+func TestParseKeyMovement(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    keyMovement
+		wantOK  bool
+	}{
+		{
+			name:    "deposit",
+			message: "guildan added 3x Godly key.",
+			want:    keyMovement{Player: "guildan", Key: "godly", Count: 3, Deposit: true},
+			wantOK:  true,
+		},
+		{
+			name:    "withdrawal",
+			message: "ImaKlutz withdrew 2x Stone key.",
+			want:    keyMovement{Player: "ImaKlutz", Key: "stone", Count: 2},
+			wantOK:  true,
+		},
+		{
+			name:    "taken from the vault",
+			message: "ImaKlutz took 1x Godly key.",
+			want:    keyMovement{Player: "ImaKlutz", Key: "godly", Count: 1},
+			wantOK:  true,
+		},
+		{
+			name:    "key without key suffix",
+			message: "yothos added 1x Krono's book.",
+			want:    keyMovement{Player: "yothos", Key: "krono's book", Count: 1, Deposit: true},
+			wantOK:  true,
+		},
+		{
+			name:    "gold is not a key",
+			message: "guildan added 1000000x Gold.",
+			wantOK:  false,
+		},
+		{
+			name:    "unrelated line",
+			message: "moraxam joined the clan.",
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseKeyMovement(tt.message)
+			if ok != tt.wantOK {
+				t.Fatalf("parseKeyMovement(%q) ok = %v, want %v", tt.message, ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("parseKeyMovement(%q) = %+v, want %+v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+	"klutco-lil-helper/internal/metrics"
+	"klutco-lil-helper/internal/model"
+	"klutco-lil-helper/internal/numfmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	marketMoversCount    = 5   // rises and falls listed in the post
+	marketMoversMinPrice = 100 // ignore items cheaper than this to avoid 1g -> 3g "+200%" noise
+	// marketMoversSlack is how far the baseline may be from a day before the latest snapshot.
+	// Beyond it snapshots are missing and the post would present a longer change as daily.
+	marketMoversSlack = 4 * time.Hour
+)
+
+// runMarketMovers posts a daily summary of the biggest price changes at the given Eastern time.
+func (b *Bot) runMarketMovers(ctx context.Context, channelName string, hour, minute int) {
+	logger := b.logFor("marketmovers")
+	for {
+		next := nextEasternTime(time.Now(), hour, minute)
+		wait := time.Until(next)
+		logger.Info("next post scheduled", "at", next.Format(time.RFC3339), "in", wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Info("context cancelled, stopping")
+			return
+		case <-timer.C:
+		}
+
+		if err := b.postMarketMovers(channelName); err != nil {
+			logger.Error("failed to post market movers", logging.Err(err))
+		}
+	}
+}
+
+// postMarketMovers compares the latest snapshot with the one a day earlier and posts the biggest moves.
+func (b *Bot) postMarketMovers(channelName string) error {
+	defer metrics.JobDuration.ObserveDuration("marketmovers", time.Now())
+
+	if b.session == nil || b.session.State == nil || b.db == nil {
+		return nil
+	}
+
+	logger := b.logFor("marketmovers")
+	channelID := b.findChannelIDByName(channelName)
+	if channelID == "" {
+		logger.Warn("channel not found", "channel_name", channelName)
+		return nil
+	}
+
+	latestAt, latest, err := model.GetLatestMarketSnapshot(b.db)
+	if err != nil {
+		return err
+	}
+	previousAt, previous, err := model.GetMarketSnapshotAt(b.db, latestAt.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if len(latest) == 0 || len(previous) == 0 {
+		logger.Info("not enough snapshots yet, skipping")
+		return nil
+	}
+	if !dailyBaseline(previousAt, latestAt) {
+		logger.Warn("no snapshot from about a day before the latest one, skipping",
+			"baseline_at", previousAt.Format(time.RFC3339), "latest_at", latestAt.Format(time.RFC3339))
+		return nil
+	}
+
+	rises, falls := market.Movers(model.LowestSellPrices(previous), model.LowestSellPrices(latest), marketMoversMinPrice, marketMoversCount)
+	embed := buildMarketMoversEmbed(market.DefaultCatalog(), rises, falls, previousAt, latestAt)
+
+	_, err = b.session.ChannelMessageSendEmbed(channelID, embed)
+	return err
+}
+
+// dailyBaseline reports whether a snapshot taken at from is a fair baseline for a daily
+// comparison with the one taken at to.
+func dailyBaseline(from, to time.Time) bool {
+	span := to.Sub(from)
+	return span >= 24*time.Hour-marketMoversSlack && span <= 24*time.Hour+marketMoversSlack
+}
+
+// buildMarketMoversEmbed renders the rises and falls between two snapshots.
+func buildMarketMoversEmbed(catalog *market.Catalog, rises, falls []market.Move, from, to time.Time) *discordgo.MessageEmbed {
+	format := func(moves []market.Move) string {
+		if len(moves) == 0 {
+			return "Nothing notable"
+		}
+		lines := make([]string, 0, len(moves))
+		for _, m := range moves {
+			lines = append(lines, fmt.Sprintf("**%s** %s → %s g (%+.1f%%)",
+				catalog.DisplayName(m.ItemID), numfmt.Thousands(int64(m.Previous)), numfmt.Thousands(int64(m.Current)), m.Change*100))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "📊 Market Movers",
+		Description: fmt.Sprintf("Lowest sell price changes over the last %s", to.Sub(from).Round(time.Hour)),
+		Color:       0xFFD700, // Gold color
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "📈 Biggest rises", Value: format(rises)},
+			{Name: "📉 Biggest falls", Value: format(falls)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Data from Idle Clans market API",
+		},
+	}
+}
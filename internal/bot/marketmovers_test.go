@@ -0,0 +1,25 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyBaseline(t *testing.T) {
+	latest := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		baseline time.Time
+		want     bool
+	}{
+		{"exactly a day", latest.Add(-24 * time.Hour), true},
+		{"missed snapshot an hour early", latest.Add(-25 * time.Hour), true},
+		{"gap of several days", latest.Add(-72 * time.Hour), false},
+		{"only a few hours of history", latest.Add(-3 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		if got := dailyBaseline(tt.baseline, latest); got != tt.want {
+			t.Errorf("%s: dailyBaseline = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+	"klutco-lil-helper/internal/metrics"
+	"klutco-lil-helper/internal/model"
+)
+
+// default values
+const (
+	defaultMarketSnapshotInterval = 1 * time.Hour
+	defaultMarketRawRetention     = 7 * 24 * time.Hour
+	defaultMarketDailyRetention   = 365 * 24 * time.Hour
+)
+
+// marketSnapshotConfig controls how often prices are stored and how long they are kept.
+type marketSnapshotConfig struct {
+	Interval       time.Duration // time between snapshots
+	RawRetention   time.Duration // raw snapshots older than this are rolled up into daily rows
+	DailyRetention time.Duration // daily rows older than this are deleted
+	AlertCooldown  time.Duration // minimum time between two DMs for the same price alert
+}
+
+// runMarketSnapshotter stores a market price snapshot on startup and then once every interval.
+func (b *Bot) runMarketSnapshotter(ctx context.Context, cfg marketSnapshotConfig) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultMarketSnapshotInterval
+	}
+	if cfg.RawRetention <= 0 {
+		cfg.RawRetention = defaultMarketRawRetention
+	}
+	if cfg.DailyRetention <= 0 {
+		cfg.DailyRetention = defaultMarketDailyRetention
+	}
+	if cfg.AlertCooldown <= 0 {
+		cfg.AlertCooldown = defaultPriceAlertCooldown
+	}
+
+	b.snapshotMarketPrices(ctx, cfg)
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.logFor("marketsnapshot").Info("stopping market snapshotter")
+			return
+		case <-ticker.C:
+			b.snapshotMarketPrices(ctx, cfg)
+		}
+	}
+}
+
+// snapshotMarketPrices fetches the latest prices, stores them and applies retention.
+func (b *Bot) snapshotMarketPrices(ctx context.Context, cfg marketSnapshotConfig) {
+	defer metrics.JobDuration.ObserveDuration("marketsnapshot", time.Now())
+
+	if b.db == nil {
+		return
+	}
+
+	logger := b.logFor("marketsnapshot")
+	fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// refreshing through the shared cache also hands the fresh prices to commands
+	snap, err := market.Prices.Refresh(fetchCtx)
+	if err != nil {
+		logger.Error("failed to fetch market prices", logging.Err(err))
+		return
+	}
+
+	now := snap.FetchedAt.UTC()
+	prices := make([]model.MarketPrice, 0, len(snap.Items))
+	for _, item := range snap.Items {
+		prices = append(prices, model.MarketPrice{
+			ItemID:          item.ItemID,
+			LowestSellPrice: item.LowestSellPrice,
+			AveragePrice:    item.AveragePrice,
+		})
+	}
+
+	if err := model.InsertMarketSnapshot(b.db, now, prices); err != nil {
+		logger.Error("failed to store snapshot", logging.Err(err))
+		return
+	}
+
+	b.checkPriceAlerts(prices, cfg.AlertCooldown)
+
+	if err := model.DownsampleMarketPrices(b.db, now.Add(-cfg.RawRetention), now.Add(-cfg.DailyRetention)); err != nil {
+		logger.Error("failed to apply retention", logging.Err(err))
+	}
+
+	logger.Debug("stored market snapshot", "items", len(prices))
+}
@@ -2,11 +2,12 @@ package bot
 
 import (
 	"context"
-	"log"
 	"strconv"
 	"strings"
 	"time"
 
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
 	"klutco-lil-helper/internal/model"
 
 	"github.com/bwmarrin/discordgo"
@@ -17,10 +18,11 @@ import (
 func (b *Bot) runBossScheduler(ctx context.Context, channelName string) {
 	// compute initial next UTC midnight
 	next := nextUTCMidnight(time.Now().UTC())
+	logger := b.logFor("messagescheduler")
 
 	for {
 		wait := time.Until(next)
-		log.Printf("[messagescheduler] boss scheduler will first run at %s (in %s)", next.Format(time.RFC3339), wait)
+		logger.Info("boss scheduler will next run", "at", next.Format(time.RFC3339), "in", wait)
 		// use a timer so we can stop it if context is cancelled
 		timer := time.NewTimer(wait)
 		select {
@@ -29,7 +31,7 @@ func (b *Bot) runBossScheduler(ctx context.Context, channelName string) {
 			if !timer.Stop() {
 				// drained or expired; nothing to do
 			}
-			log.Println("[messagescheduler] context cancelled, stopping scheduler")
+			logger.Info("context cancelled, stopping scheduler")
 			return
 		case <-timer.C:
 			// time to post
@@ -42,13 +44,13 @@ func (b *Bot) runBossScheduler(ctx context.Context, channelName string) {
 		// post the weekly message if applicable
 		if isWeekly {
 			if err := b.postBossMessage(channelName, true); err != nil {
-				log.Printf("[messagescheduler] failed to post boss message: %v", err)
+				logger.Error("failed to post boss message", logging.Err(err))
 			}
 		}
 
 		// post the daily message
 		if err := b.postBossMessage(channelName, false); err != nil {
-			log.Printf("[messagescheduler] failed to post boss message: %v", err)
+			logger.Error("failed to post boss message", logging.Err(err))
 		}
 
 		// schedule next run at the following midnight
@@ -59,23 +61,27 @@ func (b *Bot) runBossScheduler(ctx context.Context, channelName string) {
 // postBossMessage finds the channel by name, verifies permissions, sends the message, and adds reactions.
 // If weekly is true, the message uses the word 'weekly' instead of 'daily'.
 func (b *Bot) postBossMessage(channelName string, weekly bool) error {
+	defer metrics.JobDuration.ObserveDuration("bossmessage", time.Now())
+
 	if b.session == nil || b.session.State == nil {
 		return nil // session not ready; we'll try again next run
 	}
 
+	logger := b.logFor("messagescheduler").With("channel_name", channelName, "weekly", weekly)
 	channelID := b.findChannelIDByName(channelName)
 	if channelID == "" {
-		log.Printf("[messagescheduler] channel %s not found", channelName)
+		logger.Warn("channel not found")
 		return nil
 	}
+	logger = logger.With(logging.KeyChannel, channelID)
 
 	canSend, err := CanBotSend(b.session, channelID)
 	if err != nil {
-		log.Printf("[messagescheduler] permission check failed for channel %s: %v", channelName, err)
+		logger.Warn("permission check failed", logging.Err(err))
 		// continue attempting to send; try once and observe API error
 	}
 	if !canSend {
-		log.Printf("[messagescheduler] bot lacks view/send permissions for channel %s", channelName)
+		logger.Warn("bot lacks view/send permissions for channel")
 		return nil
 	}
 
@@ -88,17 +94,17 @@ func (b *Bot) postBossMessage(channelName string, weekly bool) error {
 	// Delete previous message if one exists
 	if b.db != nil {
 		if prevMsgID, err := model.GetScheduledMessage(b.db, msgType, channelID); err != nil {
-			log.Printf("[messagescheduler] failed to get previous %s message ID: %v", msgType, err)
+			logger.Error("failed to get previous message ID", "type", msgType, logging.Err(err))
 		} else if prevMsgID != "" {
 			if err := b.session.ChannelMessageDelete(channelID, prevMsgID); err != nil {
-				log.Printf("[messagescheduler] failed to delete previous %s message %s: %v", msgType, prevMsgID, err)
+				logger.Warn("failed to delete previous message", "type", msgType, logging.KeyMessageID, prevMsgID, logging.Err(err))
 				// Continue anyway; the message may have been deleted manually
 			} else {
-				log.Printf("[messagescheduler] deleted previous %s message %s", msgType, prevMsgID)
+				logger.Debug("deleted previous message", "type", msgType, logging.KeyMessageID, prevMsgID)
 			}
 			// Clean up database record whether delete succeeded or failed
 			if err := model.DeleteScheduledMessage(b.db, msgType, channelID); err != nil {
-				log.Printf("[messagescheduler] failed to delete %s message record from DB: %v", msgType, err)
+				logger.Error("failed to delete message record from DB", "type", msgType, logging.Err(err))
 			}
 		}
 	}
@@ -106,16 +112,16 @@ func (b *Bot) postBossMessage(channelName string, weekly bool) error {
 	if !weekly {
 		// Delete previous summary message if one exists
 		if prevMsgID, err := model.GetScheduledMessage(b.db, model.MessageTypeBossSummary, channelID); err != nil {
-			log.Printf("[bosssummary] failed to get previous summary message ID: %v", err)
+			logger.Error("failed to get previous summary message ID", logging.Err(err))
 		} else if prevMsgID != "" {
 			if err := b.session.ChannelMessageDelete(channelID, prevMsgID); err != nil {
-				log.Printf("[bosssummary] failed to delete previous summary message %s: %v", prevMsgID, err)
+				logger.Warn("failed to delete previous summary message", logging.KeyMessageID, prevMsgID, logging.Err(err))
 			} else {
-				log.Printf("[bosssummary] deleted previous summary message %s", prevMsgID)
+				logger.Debug("deleted previous summary message", logging.KeyMessageID, prevMsgID)
 			}
 			// Clean up database record whether delete succeeded or failed
 			if err := model.DeleteScheduledMessage(b.db, model.MessageTypeBossSummary, channelID); err != nil {
-				log.Printf("[bosssummary] failed to delete summary message record from DB: %v", err)
+				logger.Error("failed to delete summary message record from DB", logging.Err(err))
 			}
 		}
 	}
@@ -130,7 +136,7 @@ func (b *Bot) postBossMessage(channelName string, weekly bool) error {
 			m = msg
 			break
 		}
-		log.Printf("[messagescheduler] attempt %d: failed to send message to %s: %v", attempt, channelName, err)
+		logger.Warn("failed to send message", "attempt", attempt, logging.Err(err))
 		// backoff
 		time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
 	}
@@ -145,7 +151,7 @@ func (b *Bot) postBossMessage(channelName string, weekly bool) error {
 		}
 		for attempt := 1; attempt <= 3; attempt++ {
 			if err := b.session.MessageReactionAdd(m.ChannelID, m.ID, r); err != nil {
-				log.Printf("[messagescheduler] attempt %d: failed to add reaction %s: %v", attempt, r, err)
+				logger.Warn("failed to add reaction", "attempt", attempt, "reaction", r, logging.KeyMessageID, m.ID, logging.Err(err))
 				// short backoff before retrying
 				time.Sleep(time.Duration(attempt) * 300 * time.Millisecond)
 				continue
@@ -157,11 +163,11 @@ func (b *Bot) postBossMessage(channelName string, weekly bool) error {
 	// Store the new message ID for future deletion
 	if b.db != nil {
 		if err := model.UpsertScheduledMessage(b.db, msgType, channelID, m.ID); err != nil {
-			log.Printf("[messagescheduler] failed to store %s message ID: %v", msgType, err)
+			logger.Error("failed to store message ID", "type", msgType, logging.KeyMessageID, m.ID, logging.Err(err))
 		}
 	}
 
-	log.Printf("[messagescheduler] posted boss message (weekly=%v) to channel %s", weekly, channelName)
+	logger.Info("posted boss message", logging.KeyMessageID, m.ID)
 	return nil
 }
 
@@ -2,13 +2,16 @@ package bot
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"regexp"
 	"strconv"
 	"time"
 	_ "time/tzdata" // Embed timezone database for containerized environments
 
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
 	"klutco-lil-helper/internal/model"
+	"klutco-lil-helper/internal/numfmt"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -16,86 +19,178 @@ import (
 const defaultPendingChannel = "testing-ground"
 const defaultDonationChannel = "general"
 
+// Delivery retry policy: a message Discord keeps rejecting is retried with exponential
+// backoff and dead-lettered after maxDeliveryAttempts, until an admin requeues it.
+const (
+	maxDeliveryAttempts = 8
+	deliveryBaseBackoff = 30 * time.Second
+	deliveryMaxBackoff  = time.Hour
+	maxLastErrorLen     = 500
+)
+
+// relayConfig controls where and how clan log lines are relayed.
+type relayConfig struct {
+	Channel         string // relay channel name
+	DonationChannel string
+	Webhook         bool // post through a managed channel webhook under the player's name
+}
+
 // runMessageSender starts a background routine that, every 30 seconds,
-// fetches up to 10 oldest unsent clan messages and posts them to a channel named "testing-ground".
+// fetches the oldest unsent clan messages and posts them in batches to a channel named "testing-ground".
 // After successful send, the messages are marked as sent in the database.
-func (b *Bot) runMessageSender(ctx context.Context, channelName string, donationChannel string) {
+func (b *Bot) runMessageSender(ctx context.Context, cfg relayConfig) {
 	interval := 30 * time.Second
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	if channelName == "" {
-		channelName = defaultPendingChannel
+	if cfg.Channel == "" {
+		cfg.Channel = defaultPendingChannel
 	}
 
-	if donationChannel == "" {
-		donationChannel = defaultDonationChannel
+	if cfg.DonationChannel == "" {
+		cfg.DonationChannel = defaultDonationChannel
 	}
 
 	// send immediately once on startup
-	b.sendPendingMessages(channelName)
+	b.sendPendingMessages(cfg)
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Println("[messagesender] stopping message sender")
+			b.logFor("messagesender").Info("stopping message sender")
 			return
 		case <-ticker.C:
-			b.sendPendingMessages(channelName)
+			b.sendPendingMessages(cfg)
 		}
 	}
 }
 
-// sendPendingMessages fetches messages from DB and sends them to the testing-ground channel.
-func (b *Bot) sendPendingMessages(channelName string) {
+// sendPendingMessages fetches messages from DB and sends them to the testing-ground channel,
+// or to the channel the relay guild routes their category to.
+func (b *Bot) sendPendingMessages(cfg relayConfig) {
+	defer metrics.JobDuration.ObserveDuration("messagesender", time.Now())
+
+	logger := b.logFor("messagesender")
 	if b.db == nil {
-		log.Println("[messagesender] no db available")
+		logger.Warn("no db available")
 		return
 	}
 
-	msgs, err := model.GetMessages(b.db)
+	msgs, err := model.GetMessages(b.db, time.Now(), relayBatchLimit)
 	if err != nil {
-		log.Printf("[messagesender] failed to get messages: %v", err)
+		logger.Error("failed to get messages", logging.Err(err))
 		return
 	}
 	if len(msgs) == 0 {
 		return
 	}
 
-	// find channel ID for name "testing-ground" across guilds the bot is in
-	channelID := b.findChannelIDByName(channelName)
+	// find channel ID for name "testing-ground" across guilds the bot is in;
+	// that guild's routing rules decide where each category goes
+	guildID, channelID := b.findChannelByName(cfg.Channel)
 	if channelID == "" {
-		log.Printf("[messagesender] channel %v not found", channelName)
+		logger.Warn("channel not found", "channel_name", cfg.Channel)
 		return
 	}
+	logger = logger.With(logging.KeyGuild, guildID)
+
+	routes, err := model.ListRelayRoutes(b.db, guildID)
+	if err != nil {
+		logger.Error("failed to load relay routes", logging.Err(err))
+		return
+	}
+	dests, dropped := routeRelayMessages(msgs, routes, channelID)
 
 	sentIDs := make([]int64, 0, len(msgs))
-	for _, m := range msgs {
-		text := formatMessage(m)
-		if _, err := b.session.ChannelMessageSend(channelID, text); err != nil {
-			log.Printf("[messagesender] failed to send message id=%d: %v", m.ID, err)
-			// don't mark as sent; continue to next
-			continue
-		}
+	for _, m := range dropped {
+		// dropped lines count as handled; commendations don't depend on the relay
 		sentIDs = append(sentIDs, m.ID)
+		metrics.ClanMessagesDropped.Inc()
+		b.checkForLargeGoldDonation(m, logger)
+	}
+
+	loc := easternLocation(logger)
+	for _, dest := range dests {
+		destLogger := logger.With(logging.KeyChannel, dest.ChannelID)
+		for _, batch := range buildDeliveryBatches(dest.Messages, loc, maxDiscordMessageLen, cfg.Webhook) {
+			if err := b.sendRelayBatch(dest.ChannelID, batch, cfg.Webhook); err != nil {
+				// don't mark as sent; back off or dead-letter every line in the batch and continue to next.
+				// Lines that keep failing are retried on their own, so only the bad one ends up dead-lettered.
+				for _, m := range batch.Messages {
+					b.recordDeliveryFailure(m, err, time.Now(), destLogger)
+				}
+				continue
+			}
 
-		// Check if this was a large gold donation and send celebration message
-		b.checkForLargeGoldDonation(m)
+			for _, m := range batch.Messages {
+				sentIDs = append(sentIDs, m.ID)
+				metrics.ClanMessagesRelayed.Inc()
 
-		// small pause to avoid hitting rate limits
-		time.Sleep(150 * time.Millisecond)
+				// Check if this was a large gold donation and send celebration message
+				b.checkForLargeGoldDonation(m, destLogger)
+			}
+		}
 	}
 
 	if len(sentIDs) > 0 {
 		if err := model.MarkMessagesSent(b.db, sentIDs); err != nil {
-			log.Printf("[messagesender] failed to mark messages sent: %v", err)
+			logger.Error("failed to mark messages sent", "count", len(sentIDs), logging.Err(err))
+		}
+	}
+}
+
+// sendRelayBatch posts a batch as the bot, or through the channel webhook in webhook mode.
+func (b *Bot) sendRelayBatch(channelID string, batch relayBatch, webhook bool) error {
+	if webhook {
+		return b.sendRelayBatchWebhook(channelID, batch)
+	}
+	_, err := b.session.ChannelMessageSend(channelID, batch.Text)
+	return err
+}
+
+// recordDeliveryFailure schedules the next attempt for a message Discord rejected,
+// or moves it to the failed state once it has used up its attempts.
+func (b *Bot) recordDeliveryFailure(m model.ClanMessage, sendErr error, now time.Time, logger *slog.Logger) {
+	attempts := m.Attempts + 1
+	lastError := sendErr.Error()
+	if len(lastError) > maxLastErrorLen {
+		lastError = lastError[:maxLastErrorLen]
+	}
+	logger = logger.With(logging.KeyMessageID, m.ID, "attempts", attempts, logging.Err(sendErr))
+
+	if attempts >= maxDeliveryAttempts {
+		if err := model.MarkMessageFailed(b.db, m.ID, attempts, lastError); err != nil {
+			logger.Error("failed to dead-letter message", "db_error", err)
+			return
 		}
+		metrics.ClanMessagesDeadLettered.Inc()
+		logger.Error("giving up on message, moved to failed")
+		return
 	}
+
+	next := now.Add(deliveryBackoff(attempts))
+	if err := model.ScheduleMessageRetry(b.db, m.ID, attempts, lastError, next); err != nil {
+		logger.Error("failed to schedule message retry", "db_error", err)
+		return
+	}
+	logger.Warn("failed to send message, will retry", "next_attempt_at", next.Format(time.RFC3339))
+}
+
+// deliveryBackoff returns how long to wait after the given number of failed attempts.
+func deliveryBackoff(attempts int) time.Duration {
+	d := deliveryBaseBackoff
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= deliveryMaxBackoff {
+			return deliveryMaxBackoff
+		}
+	}
+	return d
 }
 
 // checkForLargeGoldDonation checks if a message is a gold donation > 1 million.
 // If so, sends an additional celebration message to the #general channel.
-func (b *Bot) checkForLargeGoldDonation(msg model.ClanMessage) {
+func (b *Bot) checkForLargeGoldDonation(msg model.ClanMessage, logger *slog.Logger) {
 	// Pattern: "playername added NNNNNNx Gold."
 	re := regexp.MustCompile(`^(.+?)\s+added\s+(\d+)x\s+Gold\.$`)
 	matches := re.FindStringSubmatch(msg.Message)
@@ -120,14 +215,14 @@ func (b *Bot) checkForLargeGoldDonation(msg model.ClanMessage) {
 	// Find the #general channel
 	generalChannelID := b.findChannelIDByName("general")
 	if generalChannelID == "" {
-		log.Println("[messagesender] general channel not found, cannot send celebration message")
+		logger.Warn("general channel not found, cannot send celebration message")
 		return
 	}
 
 	// Convert UTC timestamp to EST/EDT for the embed
 	est, err := time.LoadLocation("America/New_York")
 	if err != nil {
-		log.Printf("[messagesender] failed to load EST timezone: %v", err)
+		logger.Warn("failed to load EST timezone", logging.Err(err))
 		est = time.UTC // fallback to UTC
 	}
 	estTime := msg.Timestamp.In(est)
@@ -147,71 +242,59 @@ func (b *Bot) checkForLargeGoldDonation(msg model.ClanMessage) {
 		Fields: []*discordgo.MessageEmbedField{
 			{
 				Name:   "Amount Donated",
-				Value:  formatAmount(amount) + " Gold",
+				Value:  numfmt.Thousands(amount) + " Gold",
 				Inline: true,
 			},
 		},
 	}
 
 	if _, err := b.session.ChannelMessageSendEmbed(generalChannelID, embed); err != nil {
-		log.Printf("[messagesender] failed to send celebration message for %s: %v", playerName, err)
+		logger.Warn("failed to send celebration message", "player", playerName, logging.Err(err))
 	} else {
-		log.Printf("[messagesender] sent celebration message for %s's %d gold donation", playerName, amount)
+		logger.Info("sent celebration message", "player", playerName, "amount", amount)
 	}
 }
 
-func formatMessage(m model.ClanMessage) string {
-	// Convert UTC timestamp to EST/EDT
+// easternLocation returns the America/New_York zone relayed lines are shown in.
+func easternLocation(logger *slog.Logger) *time.Location {
 	est, err := time.LoadLocation("America/New_York")
 	if err != nil {
-		log.Printf("[messagesender] failed to load EST timezone: %v", err)
+		logger.Warn("failed to load EST timezone", logging.Err(err))
 		// fallback to UTC if timezone loading fails
-		return "`[" + m.Timestamp.Format("Jan _2 15:04") + "]` " + m.Message
-	}
-	estTime := m.Timestamp.In(est)
-	return "`[" + estTime.Format("Jan _2 15:04") + "]` " + m.Message
-}
-
-// formatAmount formats a number with comma separators (e.g., 1000000 -> "1,000,000")
-func formatAmount(n int64) string {
-	s := strconv.FormatInt(n, 10)
-	if len(s) <= 3 {
-		return s
+		return time.UTC
 	}
-
-	// Insert commas from right to left
-	var result []byte
-	for i, digit := range s {
-		if i > 0 && (len(s)-i)%3 == 0 {
-			result = append(result, ',')
-		}
-		result = append(result, byte(digit))
-	}
-	return string(result)
+	return est
 }
 
 // findChannelIDByName searches the bot's guilds for a text channel with the given name.
 // Returns the first matching channel ID or empty string if not found.
 func (b *Bot) findChannelIDByName(name string) string {
+	_, channelID := b.findChannelByName(name)
+	return channelID
+}
+
+// findChannelByName is findChannelIDByName that also returns the channel's guild.
+func (b *Bot) findChannelByName(name string) (guildID, channelID string) {
 	// Prefer cached guilds from state
+	logger := b.logFor("messagesender")
 	if b.session == nil || b.session.State == nil {
-		log.Println("[messagesender] session or state is nil")
-		return ""
+		logger.Warn("session or state is nil")
+		return "", ""
 	}
 
 	for _, g := range b.session.State.Guilds {
 		channels, err := b.session.GuildChannels(g.ID)
 		if err != nil {
-			log.Printf("[messagesender] failed to list channels for guild %s: %v", g.ID, err)
+			logger.Warn("failed to list channels", logging.KeyGuild, g.ID, logging.Err(err))
 			continue
 		}
 		for _, ch := range channels {
 			if ch.Type == discordgo.ChannelTypeGuildText && ch.Name == name {
-				return ch.ID
+				return g.ID, ch.ID
 			}
 		}
 	}
-	return ""
+	return "", ""
 }
 
 func CanBotSend(s *discordgo.Session, channelID string) (bool, error) {
@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	_ "modernc.org/sqlite"
+)
+
+// newTestDB opens a migrated in-memory database.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err := model.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestDeliveryBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := deliveryBackoff(tt.attempts); got != tt.want {
+			t.Errorf("deliveryBackoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRecordDeliveryFailureDeadLetters(t *testing.T) {
+	db := newTestDB(t)
+	b := &Bot{db: db}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, err := model.InsertClanMessage(db, model.ClanMessage{ClanName: "KlutzCo", MemberUsername: "x", Message: "x joined the clan.", Timestamp: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	sendErr := errors.New("HTTP 400 Bad Request")
+	for attempt := 1; attempt <= maxDeliveryAttempts; attempt++ {
+		// jump past any backoff so the message is due again
+		now = now.Add(2 * time.Hour)
+		msgs, err := model.GetMessages(db, now, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(msgs) != 1 {
+			t.Fatalf("attempt %d: %d due messages, want 1", attempt, len(msgs))
+		}
+		b.recordDeliveryFailure(msgs[0], sendErr, now, logging.Discard())
+	}
+
+	if msgs, _ := model.GetMessages(db, now.Add(24*time.Hour), 10); len(msgs) != 0 {
+		t.Errorf("dead-lettered message still pending: %+v", msgs)
+	}
+	failed, err := model.ListFailedMessages(db, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0].Attempts != maxDeliveryAttempts || failed[0].LastError != sendErr.Error() {
+		t.Errorf("failed = %+v, want one message with %d attempts", failed, maxDeliveryAttempts)
+	}
+}
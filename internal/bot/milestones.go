@@ -0,0 +1,98 @@
+package bot
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// milestoneChannelName is where milestones are celebrated, next to vault commendations.
+const milestoneChannelName = "general"
+
+// announceMilestones celebrates the level milestones a member reached since their previous
+// snapshot, unless they opted out with /milestones.
+func (b *Bot) announceMilestones(gameName, discordID string, milestones []idleclans.Milestone, logger *slog.Logger) {
+	if len(milestones) == 0 {
+		return
+	}
+	l := logger.With("player", gameName)
+
+	if discordID != "" {
+		optedOut, err := model.MilestoneOptedOut(b.db, discordID)
+		if err != nil {
+			l.Error("failed to check milestone opt-out", logging.Err(err))
+			return
+		}
+		if optedOut {
+			l.Debug("member opted out of milestone announcements", "milestones", len(milestones))
+			return
+		}
+	}
+
+	channelID := b.findChannelIDByName(milestoneChannelName)
+	if channelID == "" {
+		l.Warn("general channel not found, cannot send milestone message")
+		return
+	}
+
+	embed := milestoneEmbed(gameName, discordID, milestones, time.Now().In(easternLocation(l)))
+	if _, err := b.session.ChannelMessageSendEmbed(channelID, embed); err != nil {
+		l.Warn("failed to send milestone message", logging.Err(err))
+	} else {
+		l.Info("sent milestone message", "milestones", len(milestones))
+	}
+}
+
+// milestoneEmbed builds the celebration of a member's milestones in the style of the
+// vault donation commendation.
+func milestoneEmbed(gameName, discordID string, milestones []idleclans.Milestone, at time.Time) *discordgo.MessageEmbed {
+	member := "**" + gameName + "**"
+	if discordID != "" {
+		member = "<@" + discordID + "> (**" + gameName + "**)"
+	}
+
+	reached := make([]string, len(milestones))
+	for i, m := range milestones {
+		reached[i] = m.String()
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🔔🎉 Leadership Commendation",
+		Description: "Leadership commends " + member + " for reaching " + joinMilestones(reached) + ". This dedication to personal development exemplifies KlutzCo values. Well done.",
+		Color:       0xFFD700, // Gold color
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: at.Format("Jan _2, 2006 at 3:04 PM MST") + " · /milestones to opt out",
+		},
+	}
+	for _, m := range milestones {
+		field := &discordgo.MessageEmbedField{Inline: true}
+		switch {
+		case m.IsTotal():
+			field.Name = "Total Level"
+			field.Value = strconv.Itoa(m.Level)
+		case m.IsMax():
+			field.Name = idleclans.Skill{Name: m.Skill}.DisplayName()
+			field.Value = "🏆 Level " + strconv.Itoa(m.Level) + " (max)"
+		default:
+			field.Name = idleclans.Skill{Name: m.Skill}.DisplayName()
+			field.Value = "Level " + strconv.Itoa(m.Level)
+		}
+		embed.Fields = append(embed.Fields, field)
+	}
+	return embed
+}
+
+// joinMilestones joins descriptions as "a", "a and b" or "a, b and c".
+func joinMilestones(parts []string) string {
+	if len(parts) <= 1 {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
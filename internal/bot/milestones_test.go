@@ -0,0 +1,33 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+)
+
+func TestMilestoneEmbed(t *testing.T) {
+	at := time.Date(2026, 10, 5, 0, 5, 0, 0, time.UTC)
+	milestones := []idleclans.Milestone{
+		{Skill: "attack", Level: 70},
+		{Skill: "woodcutting", Level: 120},
+		{Level: 1000},
+	}
+
+	embed := milestoneEmbed("ImaKlutz", "42", milestones, at)
+
+	want := "Leadership commends <@42> (**ImaKlutz**) for reaching level 70 Attack, max level Woodcutting and total level 1000."
+	if !strings.HasPrefix(embed.Description, want) {
+		t.Errorf("description = %q", embed.Description)
+	}
+	if len(embed.Fields) != 3 || embed.Fields[1].Value != "🏆 Level 120 (max)" || embed.Fields[2].Name != "Total Level" {
+		t.Errorf("fields = %+v", embed.Fields)
+	}
+
+	unlinked := milestoneEmbed("ImaKlutz", "", milestones[:1], at)
+	if !strings.HasPrefix(unlinked.Description, "Leadership commends **ImaKlutz** for reaching level 70 Attack.") {
+		t.Errorf("unlinked description = %q", unlinked.Description)
+	}
+}
@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+	"klutco-lil-helper/internal/model"
+	"klutco-lil-helper/internal/numfmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const defaultPriceAlertCooldown = 6 * time.Hour
+
+// alertAction is what should happen to an alert after looking at the latest price.
+type alertAction int
+
+const (
+	alertKeep   alertAction = iota // nothing to do
+	alertNotify                    // DM the user and mark the alert triggered
+	alertRearm                     // price moved back; allow the alert to fire again
+)
+
+// evaluatePriceAlert decides what to do with an alert given the latest price.
+// An alert fires once when the threshold is crossed and re-arms when the price
+// moves back; the cooldown stops a price oscillating around the threshold from
+// re-firing it too often.
+func evaluatePriceAlert(a model.PriceAlert, price float64, now time.Time, cooldown time.Duration) alertAction {
+	if !a.Crossed(price) {
+		if a.Triggered {
+			return alertRearm
+		}
+		return alertKeep
+	}
+	if a.Triggered {
+		return alertKeep
+	}
+	if !a.LastNotifiedAt.IsZero() && now.Sub(a.LastNotifiedAt) < cooldown {
+		return alertKeep
+	}
+	return alertNotify
+}
+
+// checkPriceAlerts evaluates every alert against a freshly stored snapshot and DMs subscribers.
+func (b *Bot) checkPriceAlerts(prices []model.MarketPrice, cooldown time.Duration) {
+	if b.db == nil || b.session == nil {
+		return
+	}
+
+	logger := b.logFor("pricealerts")
+	alerts, err := model.GetAllPriceAlerts(b.db)
+	if err != nil {
+		logger.Error("failed to load alerts", logging.Err(err))
+		return
+	}
+	if len(alerts) == 0 {
+		return
+	}
+
+	byItem := make(map[int]model.MarketPrice, len(prices))
+	for _, p := range prices {
+		byItem[p.ItemID] = p
+	}
+
+	now := time.Now().UTC()
+	for _, a := range alerts {
+		p, ok := byItem[a.ItemID]
+		if !ok || p.LowestSellPrice <= 0 {
+			continue
+		}
+
+		switch evaluatePriceAlert(a, p.LowestSellPrice, now, cooldown) {
+		case alertRearm:
+			if err := model.ResetPriceAlert(b.db, a.ID); err != nil {
+				logger.Error("failed to re-arm alert", "alert", a.ID, logging.Err(err))
+			}
+		case alertNotify:
+			if err := b.sendPriceAlert(a, p); err != nil {
+				logger.Warn("failed to notify user", "user", a.UserID, "alert", a.ID, logging.Err(err))
+				continue
+			}
+			if err := model.MarkPriceAlertNotified(b.db, a.ID, now); err != nil {
+				logger.Error("failed to mark alert notified", "alert", a.ID, logging.Err(err))
+			}
+		}
+	}
+}
+
+// sendPriceAlert DMs the subscriber that their threshold was crossed.
+func (b *Bot) sendPriceAlert(a model.PriceAlert, p model.MarketPrice) error {
+	ch, err := b.session.UserChannelCreate(a.UserID)
+	if err != nil {
+		return err
+	}
+
+	name := market.DefaultCatalog().DisplayName(a.ItemID)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📉 Price Alert: " + name,
+		Description: fmt.Sprintf("**%s** is now listed at **%s g**, %s your threshold of %s g.", name, numfmt.Thousands(int64(p.LowestSellPrice)), a.Direction, numfmt.Thousands(int64(a.Threshold))),
+		Color:       0x00FF00,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Alert #%d · remove it with /price-alert remove", a.ID),
+		},
+	}
+	if a.Direction == model.AlertAbove {
+		embed.Title = "📈 Price Alert: " + name
+		embed.Color = 0xFF0000
+	}
+
+	_, err = b.session.ChannelMessageSendEmbed(ch.ID, embed)
+	return err
+}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"klutco-lil-helper/internal/model"
+)
+
+func TestEvaluatePriceAlert(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cooldown := 6 * time.Hour
+
+	tests := []struct {
+		name  string
+		alert model.PriceAlert
+		price float64
+		want  alertAction
+	}{
+		{
+			name:  "below threshold fires",
+			alert: model.PriceAlert{Direction: model.AlertBelow, Threshold: 100},
+			price: 90,
+			want:  alertNotify,
+		},
+		{
+			name:  "above threshold is quiet for below alert",
+			alert: model.PriceAlert{Direction: model.AlertBelow, Threshold: 100},
+			price: 110,
+			want:  alertKeep,
+		},
+		{
+			name:  "above alert fires",
+			alert: model.PriceAlert{Direction: model.AlertAbove, Threshold: 100},
+			price: 100,
+			want:  alertNotify,
+		},
+		{
+			name:  "already triggered stays quiet",
+			alert: model.PriceAlert{Direction: model.AlertBelow, Threshold: 100, Triggered: true, LastNotifiedAt: now.Add(-48 * time.Hour)},
+			price: 90,
+			want:  alertKeep,
+		},
+		{
+			name:  "price moved back re-arms",
+			alert: model.PriceAlert{Direction: model.AlertBelow, Threshold: 100, Triggered: true, LastNotifiedAt: now.Add(-time.Hour)},
+			price: 120,
+			want:  alertRearm,
+		},
+		{
+			name:  "re-armed alert within cooldown stays quiet",
+			alert: model.PriceAlert{Direction: model.AlertBelow, Threshold: 100, LastNotifiedAt: now.Add(-time.Hour)},
+			price: 90,
+			want:  alertKeep,
+		},
+		{
+			name:  "re-armed alert after cooldown fires again",
+			alert: model.PriceAlert{Direction: model.AlertBelow, Threshold: 100, LastNotifiedAt: now.Add(-7 * time.Hour)},
+			price: 90,
+			want:  alertNotify,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evaluatePriceAlert(tt.alert, tt.price, now, cooldown); got != tt.want {
+				t.Errorf("evaluatePriceAlert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
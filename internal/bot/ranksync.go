@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
+	"klutco-lil-helper/internal/model"
+	"klutco-lil-helper/internal/ranksync"
+)
+
+// defaultRankSyncInterval is how often in-game ranks are mirrored onto Discord roles.
+const defaultRankSyncInterval = time.Hour
+
+// runRankSync syncs the rank roles of every guild that mapped one, on startup and then every interval.
+// In dry-run mode the changes are only logged.
+func (b *Bot) runRankSync(ctx context.Context, interval time.Duration, dryRun bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	b.syncRankRoles(ctx, dryRun)
+
+	for {
+		select {
+		case <-ctx.Done():
+			b.logFor("ranksync").Info("stopping rank sync")
+			return
+		case <-ticker.C:
+			b.syncRankRoles(ctx, dryRun)
+		}
+	}
+}
+
+func (b *Bot) syncRankRoles(ctx context.Context, dryRun bool) {
+	defer metrics.JobDuration.ObserveDuration("ranksync", time.Now())
+
+	logger := b.logFor("ranksync")
+	guilds, err := model.ListRankRoleGuilds(b.db)
+	if err != nil {
+		logger.Error("failed to list guilds with rank roles", logging.Err(err))
+		return
+	}
+
+	for _, guildID := range guilds {
+		report, err := ranksync.Sync(ctx, b.session, b.db, idleclans.Default, model.ClanName, guildID, dryRun, logger)
+		if err != nil {
+			logger.Error("failed to sync rank roles", logging.KeyGuild, guildID, logging.Err(err))
+			continue
+		}
+		if len(report.Changes) > 0 || len(report.NotInGuild) > 0 {
+			logger.Info("synced rank roles", logging.KeyGuild, guildID, "dry_run", dryRun,
+				"changes", len(report.Changes), "failed", len(report.Failed), "not_in_guild", len(report.NotInGuild))
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"klutco-lil-helper/internal/model"
+)
+
+const (
+	// maxDiscordMessageLen is Discord's limit on the content of a single message.
+	maxDiscordMessageLen = 2000
+	// relayBatchLimit is how many pending lines one sender run picks up.
+	relayBatchLimit = 50
+	// soloAfterAttempts is how many failed batched posts a line gets before it is sent on its
+	// own, so one line Discord rejects can't dead-letter the good lines batched with it.
+	soloAfterAttempts = 2
+)
+
+// relayBatch is one Discord message worth of clan log lines.
+type relayBatch struct {
+	Text     string
+	Author   string // member of every line, only set when batches are split by author
+	Messages []model.ClanMessage
+}
+
+// buildRelayBatches packs consecutive lines into as few messages as possible.
+// Lines are grouped under a "`[Jan _2 15:04]`" header per minute (in loc), and a
+// batch never exceeds limit characters; a line too long to fit on its own is truncated.
+// With byAuthor, a new batch starts whenever the member changes so each batch can be
+// posted under that member's name.
+func buildRelayBatches(msgs []model.ClanMessage, loc *time.Location, limit int, byAuthor bool) []relayBatch {
+	var batches []relayBatch
+	var cur relayBatch
+	var sb strings.Builder
+	lastHeader := ""
+
+	flush := func() {
+		if len(cur.Messages) > 0 {
+			cur.Text = sb.String()
+			batches = append(batches, cur)
+		}
+		cur = relayBatch{}
+		sb.Reset()
+		lastHeader = ""
+	}
+
+	for _, m := range msgs {
+		header := relayMinuteHeader(m.Timestamp, loc)
+		line := m.Message
+
+		// what this line adds to the current batch
+		addition := line
+		if header != lastHeader {
+			addition = header + "\n" + line
+		}
+		if sb.Len() > 0 {
+			addition = "\n" + addition
+		}
+
+		authorChanged := byAuthor && len(cur.Messages) > 0 && cur.Author != m.MemberUsername
+		if sb.Len() > 0 && (authorChanged || sb.Len()+len(addition) > limit) {
+			flush()
+			addition = header + "\n" + line
+		}
+		if byAuthor {
+			cur.Author = m.MemberUsername
+		}
+		if sb.Len() == 0 && len(addition) > limit {
+			addition = truncateToLen(addition, limit)
+		}
+
+		sb.WriteString(addition)
+		cur.Messages = append(cur.Messages, m)
+		lastHeader = header
+	}
+	flush()
+
+	return batches
+}
+
+// buildDeliveryBatches batches lines like buildRelayBatches, except that lines which already
+// failed soloAfterAttempts times get a batch of their own. Batches keep the order of msgs.
+func buildDeliveryBatches(msgs []model.ClanMessage, loc *time.Location, limit int, byAuthor bool) []relayBatch {
+	var batches []relayBatch
+	start := 0
+	for i, m := range msgs {
+		if m.Attempts < soloAfterAttempts {
+			continue
+		}
+		batches = append(batches, buildRelayBatches(msgs[start:i], loc, limit, byAuthor)...)
+		batches = append(batches, buildRelayBatches(msgs[i:i+1], loc, limit, byAuthor)...)
+		start = i + 1
+	}
+	return append(batches, buildRelayBatches(msgs[start:], loc, limit, byAuthor)...)
+}
+
+// relayMinuteHeader formats the minute a line was logged, e.g. "`[Mar  1 12:05]`".
+func relayMinuteHeader(t time.Time, loc *time.Location) string {
+	return "`[" + t.In(loc).Format("Jan _2 15:04") + "]`"
+}
+
+// truncateToLen shortens s to at most n bytes without splitting a UTF-8 character,
+// marking the cut with an ellipsis.
+func truncateToLen(s string, n int) string {
+	const ellipsis = "…"
+	if len(s) <= n {
+		return s
+	}
+	cut := n - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
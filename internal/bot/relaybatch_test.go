@@ -0,0 +1,138 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"klutco-lil-helper/internal/model"
+)
+
+func TestBuildRelayBatchesGroupsByMinute(t *testing.T) {
+	base := time.Date(2026, 3, 1, 17, 5, 0, 0, time.UTC)
+	msgs := []model.ClanMessage{
+		{ID: 1, Message: "a added 5x Gold.", Timestamp: base.Add(10 * time.Second)},
+		{ID: 2, Message: "b added 1x Godly key.", Timestamp: base.Add(40 * time.Second)},
+		{ID: 3, Message: "c joined the clan.", Timestamp: base.Add(2 * time.Minute)},
+	}
+
+	batches := buildRelayBatches(msgs, time.UTC, maxDiscordMessageLen, false)
+	if len(batches) != 1 {
+		t.Fatalf("got %d batches, want 1", len(batches))
+	}
+
+	want := "`[Mar  1 17:05]`\na added 5x Gold.\nb added 1x Godly key.\n`[Mar  1 17:07]`\nc joined the clan."
+	if batches[0].Text != want {
+		t.Errorf("text =\n%s\nwant\n%s", batches[0].Text, want)
+	}
+	if len(batches[0].Messages) != 3 {
+		t.Errorf("batch carries %d messages, want 3", len(batches[0].Messages))
+	}
+}
+
+func TestBuildDeliveryBatchesIsolatesRetriedLines(t *testing.T) {
+	base := time.Date(2026, 3, 1, 17, 5, 0, 0, time.UTC)
+	msgs := []model.ClanMessage{
+		{ID: 1, Message: "a added 5x Gold.", Timestamp: base, Attempts: 1},
+		{ID: 2, Message: "b added 1x Godly key.", Timestamp: base, Attempts: soloAfterAttempts},
+		{ID: 3, Message: "c joined the clan.", Timestamp: base},
+		{ID: 4, Message: "d left the clan.", Timestamp: base},
+		{ID: 5, Message: "e left the clan.", Timestamp: base, Attempts: soloAfterAttempts + 3},
+	}
+
+	batches := buildDeliveryBatches(msgs, time.UTC, maxDiscordMessageLen, false)
+	var got [][]int64
+	for _, b := range batches {
+		var ids []int64
+		for _, m := range b.Messages {
+			ids = append(ids, m.ID)
+		}
+		got = append(got, ids)
+	}
+	want := [][]int64{{1}, {2}, {3, 4}, {5}}
+	if len(got) != len(want) {
+		t.Fatalf("batches = %v, want %v", got, want)
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) || got[i][0] != want[i][0] || got[i][len(got[i])-1] != want[i][len(want[i])-1] {
+			t.Errorf("batches = %v, want %v", got, want)
+			break
+		}
+	}
+	if batches[1].Text != "`[Mar  1 17:05]`\nb added 1x Godly key." {
+		t.Errorf("solo batch text = %q", batches[1].Text)
+	}
+}
+
+func TestBuildRelayBatchesRespectsLimit(t *testing.T) {
+	base := time.Date(2026, 3, 1, 17, 5, 0, 0, time.UTC)
+	var msgs []model.ClanMessage
+	for i := 0; i < 60; i++ {
+		msgs = append(msgs, model.ClanMessage{
+			ID:        int64(i + 1),
+			Message:   strings.Repeat("x", 80),
+			Timestamp: base,
+		})
+	}
+
+	batches := buildRelayBatches(msgs, time.UTC, maxDiscordMessageLen, false)
+	if len(batches) < 2 {
+		t.Fatalf("got %d batches, want the lines split across several", len(batches))
+	}
+
+	total := 0
+	for i, b := range batches {
+		if len(b.Text) > maxDiscordMessageLen {
+			t.Errorf("batch %d is %d characters, over the limit", i, len(b.Text))
+		}
+		// every batch restates the minute so it reads on its own
+		if !strings.HasPrefix(b.Text, "`[Mar  1 17:05]`\n") {
+			t.Errorf("batch %d does not start with its minute header: %q", i, b.Text[:20])
+		}
+		total += len(b.Messages)
+	}
+	if total != len(msgs) {
+		t.Errorf("batches carry %d messages, want %d", total, len(msgs))
+	}
+}
+
+func TestBuildRelayBatchesTruncatesOversizedLine(t *testing.T) {
+	msgs := []model.ClanMessage{{ID: 1, Message: strings.Repeat("é", 1500), Timestamp: time.Now()}}
+
+	batches := buildRelayBatches(msgs, time.UTC, maxDiscordMessageLen, false)
+	if len(batches) != 1 {
+		t.Fatalf("got %d batches, want 1", len(batches))
+	}
+	text := batches[0].Text
+	if len(text) > maxDiscordMessageLen || !strings.HasSuffix(text, "…") {
+		t.Errorf("oversized line not truncated: %d bytes, suffix %q", len(text), text[len(text)-3:])
+	}
+}
+
+func TestBuildRelayBatchesByAuthor(t *testing.T) {
+	base := time.Date(2026, 3, 1, 17, 5, 0, 0, time.UTC)
+	msgs := []model.ClanMessage{
+		{ID: 1, MemberUsername: "alice", Message: "alice added 5x Gold.", Timestamp: base},
+		{ID: 2, MemberUsername: "alice", Message: "alice added 1x Godly key.", Timestamp: base},
+		{ID: 3, MemberUsername: "bob", Message: "bob withdrew 2x Gold.", Timestamp: base},
+		{ID: 4, MemberUsername: "alice", Message: "alice added 3x Gold.", Timestamp: base},
+	}
+
+	batches := buildRelayBatches(msgs, time.UTC, maxDiscordMessageLen, true)
+
+	var authors []string
+	for _, b := range batches {
+		authors = append(authors, b.Author)
+	}
+	if got := strings.Join(authors, ","); got != "alice,bob,alice" {
+		t.Fatalf("batch authors = %s, want alice,bob,alice", got)
+	}
+	if len(batches[0].Messages) != 2 {
+		t.Errorf("first batch carries %d lines, want alice's two", len(batches[0].Messages))
+	}
+	for i, b := range batches {
+		if !strings.HasPrefix(b.Text, "`[Mar  1 17:05]`\n") {
+			t.Errorf("batch %d lacks its minute header: %q", i, b.Text)
+		}
+	}
+}
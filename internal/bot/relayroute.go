@@ -0,0 +1,42 @@
+package bot
+
+import (
+	"klutco-lil-helper/internal/model"
+)
+
+// relayDestination is a channel and the pending lines routed to it, oldest first.
+type relayDestination struct {
+	ChannelID string
+	Messages  []model.ClanMessage
+}
+
+// routeRelayMessages splits pending lines by the channel their category is routed to.
+// Categories without a route go to defaultChannelID; lines of dropped categories are
+// returned separately. Destinations keep the order in which they first appear.
+func routeRelayMessages(msgs []model.ClanMessage, routes map[model.EventCategory]model.RelayRoute, defaultChannelID string) ([]relayDestination, []model.ClanMessage) {
+	var dests []relayDestination
+	var dropped []model.ClanMessage
+	index := make(map[string]int)
+
+	for _, m := range msgs {
+		channelID := defaultChannelID
+		if r, ok := routes[model.ClassifyClanMessage(m.Message)]; ok {
+			if r.Drop {
+				dropped = append(dropped, m)
+				continue
+			}
+			if r.ChannelID != "" {
+				channelID = r.ChannelID
+			}
+		}
+
+		i, ok := index[channelID]
+		if !ok {
+			i = len(dests)
+			index[channelID] = i
+			dests = append(dests, relayDestination{ChannelID: channelID})
+		}
+		dests[i].Messages = append(dests[i].Messages, m)
+	}
+	return dests, dropped
+}
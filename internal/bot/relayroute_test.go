@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"testing"
+
+	"klutco-lil-helper/internal/model"
+)
+
+func TestRouteRelayMessages(t *testing.T) {
+	msgs := []model.ClanMessage{
+		{ID: 1, Message: "a joined the clan."},
+		{ID: 2, Message: "a added 5x Gold."},
+		{ID: 3, Message: "b promoted a to Officer."},
+		{ID: 4, Message: "b withdrew 1x Godly key."},
+		{ID: 5, Message: "c left the clan."},
+	}
+	routes := map[model.EventCategory]model.RelayRoute{
+		model.CategoryVault:     {Category: model.CategoryVault, ChannelID: "vault"},
+		model.CategoryPromotion: {Category: model.CategoryPromotion, Drop: true},
+	}
+
+	dests, dropped := routeRelayMessages(msgs, routes, "relay")
+
+	if len(dests) != 2 {
+		t.Fatalf("got %d destinations, want 2", len(dests))
+	}
+	if dests[0].ChannelID != "relay" || !sameIDs(dests[0].Messages, 1, 5) {
+		t.Errorf("first destination = %s %v, want relay [1 5]", dests[0].ChannelID, dests[0].Messages)
+	}
+	if dests[1].ChannelID != "vault" || !sameIDs(dests[1].Messages, 2, 4) {
+		t.Errorf("second destination = %s %v, want vault [2 4]", dests[1].ChannelID, dests[1].Messages)
+	}
+	if !sameIDs(dropped, 3) {
+		t.Errorf("dropped = %v, want [3]", dropped)
+	}
+}
+
+func sameIDs(msgs []model.ClanMessage, ids ...int64) bool {
+	if len(msgs) != len(ids) {
+		return false
+	}
+	for i, m := range msgs {
+		if m.ID != ids[i] {
+			return false
+		}
+	}
+	return true
+}
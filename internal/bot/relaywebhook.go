@@ -0,0 +1,141 @@
+package bot
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// relayWebhookName identifies the webhook the bot creates in relay channels.
+	relayWebhookName = "Lil Helper Relay"
+	// avatarCacheTTL is how long a member's avatar URL is reused before asking Discord again.
+	avatarCacheTTL = 6 * time.Hour
+	// maxWebhookUsernameLen is Discord's limit on a webhook message's username.
+	maxWebhookUsernameLen = 80
+)
+
+// relayWebhooks caches the managed webhook of each relay channel and linked members' avatars.
+type relayWebhooks struct {
+	mu        sync.Mutex
+	byChannel map[string]*discordgo.Webhook
+	avatars   map[string]cachedAvatar // by Discord user ID
+}
+
+type cachedAvatar struct {
+	url       string
+	fetchedAt time.Time
+}
+
+// relayWebhook returns the bot's webhook for channelID, reusing one it created earlier
+// (even in a previous run) before creating a new one.
+func (b *Bot) relayWebhook(channelID string) (*discordgo.Webhook, error) {
+	b.webhooks.mu.Lock()
+	defer b.webhooks.mu.Unlock()
+
+	if wh, ok := b.webhooks.byChannel[channelID]; ok {
+		return wh, nil
+	}
+
+	hooks, err := b.session.ChannelWebhooks(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var wh *discordgo.Webhook
+	for _, h := range hooks {
+		if h.Name == relayWebhookName && h.Token != "" && h.User != nil && b.session.State.User != nil && h.User.ID == b.session.State.User.ID {
+			wh = h
+			break
+		}
+	}
+	if wh == nil {
+		if wh, err = b.session.WebhookCreate(channelID, relayWebhookName, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if b.webhooks.byChannel == nil {
+		b.webhooks.byChannel = make(map[string]*discordgo.Webhook)
+	}
+	b.webhooks.byChannel[channelID] = wh
+	return wh, nil
+}
+
+// forgetRelayWebhook drops a cached webhook, e.g. after someone deleted it in Discord.
+func (b *Bot) forgetRelayWebhook(channelID string) {
+	b.webhooks.mu.Lock()
+	delete(b.webhooks.byChannel, channelID)
+	b.webhooks.mu.Unlock()
+}
+
+// sendRelayBatchWebhook posts a batch through the channel's webhook as the in-game member.
+func (b *Bot) sendRelayBatchWebhook(channelID string, batch relayBatch) error {
+	wh, err := b.relayWebhook(channelID)
+	if err != nil {
+		return err
+	}
+
+	params := &discordgo.WebhookParams{
+		Content:   batch.Text,
+		Username:  webhookUsername(batch.Author),
+		AvatarURL: b.memberAvatarURL(batch.Author),
+		// relayed lines are player-controlled text; never let them ping anyone
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	}
+
+	_, err = b.session.WebhookExecute(wh.ID, wh.Token, false, params)
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound {
+		// the webhook was deleted; recreate it on the next attempt
+		b.forgetRelayWebhook(channelID)
+	}
+	return err
+}
+
+// memberAvatarURL returns the avatar of the Discord account linked to a game name, or "" when unknown.
+func (b *Bot) memberAvatarURL(gameName string) string {
+	discordID, ok, err := model.LinkedDiscordID(b.db, gameName)
+	if err != nil || !ok || discordID == "" {
+		return ""
+	}
+
+	b.webhooks.mu.Lock()
+	cached, ok := b.webhooks.avatars[discordID]
+	b.webhooks.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < avatarCacheTTL {
+		return cached.url
+	}
+
+	user, err := b.session.User(discordID)
+	if err != nil {
+		// keep using a stale avatar rather than none
+		return cached.url
+	}
+	url := user.AvatarURL("128")
+
+	b.webhooks.mu.Lock()
+	if b.webhooks.avatars == nil {
+		b.webhooks.avatars = make(map[string]cachedAvatar)
+	}
+	b.webhooks.avatars[discordID] = cachedAvatar{url: url, fetchedAt: time.Now()}
+	b.webhooks.mu.Unlock()
+	return url
+}
+
+// webhookUsername fits a game name into Discord's webhook username rules.
+func webhookUsername(name string) string {
+	r := []rune(name)
+	if len(r) == 0 {
+		return "Clan"
+	}
+	if len(r) > maxWebhookUsernameLen {
+		r = r[:maxWebhookUsernameLen]
+	}
+	return string(r)
+}
@@ -0,0 +1,18 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWebhookUsername(t *testing.T) {
+	if got := webhookUsername(""); got != "Clan" {
+		t.Errorf("webhookUsername(\"\") = %q, want Clan", got)
+	}
+	if got := webhookUsername("ImaKlutz"); got != "ImaKlutz" {
+		t.Errorf("webhookUsername(ImaKlutz) = %q", got)
+	}
+	if got := webhookUsername(strings.Repeat("é", 100)); len([]rune(got)) != maxWebhookUsernameLen {
+		t.Errorf("long name kept %d runes, want %d", len([]rune(got)), maxWebhookUsernameLen)
+	}
+}
@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultWelcomeMessage  = "👋 Welcome to {clan}, {member}!"
+	defaultFarewellMessage = "👋 {member} has left {clan}. Farewell!"
+	// rosterAnnounceMaxAge keeps a first fetch of old history from greeting members again.
+	rosterAnnounceMaxAge = 24 * time.Hour
+)
+
+// handleRosterChanges records join and leave dates for newly stored clan log lines and,
+// for recent ones, runs the welcome or farewell workflow of every configured guild.
+func (b *Bot) handleRosterChanges(msgs []model.ClanMessage, now time.Time) {
+	logger := b.logFor("roster")
+
+	for _, m := range msgs {
+		gameName, joined, ok := model.ParseRosterChange(m.Message)
+		if !ok {
+			continue
+		}
+		l := logger.With("player", gameName, "joined", joined)
+
+		record := model.RecordMemberLeft
+		if joined {
+			record = model.RecordMemberJoined
+		}
+		if err := record(b.db, gameName, m.Timestamp); err != nil {
+			l.Error("failed to record roster change", logging.Err(err))
+		}
+
+		if now.Sub(m.Timestamp) > rosterAnnounceMaxAge {
+			l.Debug("skipping welcome workflow for old roster line", "timestamp", m.Timestamp)
+			continue
+		}
+		b.announceRosterChange(m, gameName, joined, l)
+	}
+}
+
+// announceRosterChange posts the welcome or farewell message and updates the member's clan role.
+func (b *Bot) announceRosterChange(m model.ClanMessage, gameName string, joined bool, logger *slog.Logger) {
+	settings, err := model.ListWelcomeSettings(b.db)
+	if err != nil {
+		logger.Error("failed to load welcome settings", logging.Err(err))
+		return
+	}
+	if len(settings) == 0 {
+		return
+	}
+
+	discordID, linked, err := model.LinkedDiscordID(b.db, gameName)
+	if err != nil {
+		logger.Error("failed to look up linked account", logging.Err(err))
+		return
+	}
+	if !linked {
+		discordID = ""
+	}
+
+	for _, s := range settings {
+		l := logger.With(logging.KeyGuild, s.GuildID, logging.KeyChannel, s.ChannelID)
+
+		msg := &discordgo.MessageSend{
+			Content: rosterMessage(s, m.ClanName, gameName, discordID, joined),
+			// only ever ping the member themselves, whatever the template says
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		}
+		if discordID != "" && joined {
+			msg.AllowedMentions.Users = []string{discordID}
+		}
+		if _, err := b.session.ChannelMessageSendComplex(s.ChannelID, msg); err != nil {
+			l.Warn("failed to post roster message", logging.Err(err))
+		}
+
+		if s.RoleID == "" || discordID == "" {
+			continue
+		}
+		if joined {
+			err = b.session.GuildMemberRoleAdd(s.GuildID, discordID, s.RoleID)
+		} else {
+			err = b.session.GuildMemberRoleRemove(s.GuildID, discordID, s.RoleID)
+		}
+		if err != nil {
+			l.Warn("failed to update clan role", "role", s.RoleID, logging.Err(err))
+		}
+	}
+}
+
+// rosterMessage fills in a guild's welcome or farewell template. {member} becomes a mention
+// for linked members and the bold game name otherwise; {clan} becomes the clan name.
+// Unlinked newcomers are asked to request a /link of their account.
+func rosterMessage(s model.WelcomeSettings, clanName, gameName, discordID string, joined bool) string {
+	tmpl := s.FarewellMessage
+	if tmpl == "" {
+		tmpl = defaultFarewellMessage
+	}
+	if joined {
+		tmpl = s.WelcomeMessage
+		if tmpl == "" {
+			tmpl = defaultWelcomeMessage
+		}
+	}
+
+	member := "**" + gameName + "**"
+	if discordID != "" {
+		member = "<@" + discordID + ">"
+	}
+	if clanName == "" {
+		clanName = "the clan"
+	}
+	text := strings.NewReplacer("{member}", member, "{clan}", clanName).Replace(tmpl)
+
+	if joined && discordID == "" {
+		text += "\nLink your Discord account with `/link name:" + gameName + "`"
+		if s.RoleID != "" {
+			text += " to get the <@&" + s.RoleID + "> role once an officer approves it"
+		}
+		text += "."
+	}
+	return text
+}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+)
+
+func TestRosterMessage(t *testing.T) {
+	s := model.WelcomeSettings{RoleID: "55"}
+
+	got := rosterMessage(s, "KlutzCo", "newbie", "", true)
+	if !strings.HasPrefix(got, "👋 Welcome to KlutzCo, **newbie**!") {
+		t.Errorf("welcome = %q", got)
+	}
+	if !strings.Contains(got, "`/link name:newbie`") || !strings.Contains(got, "<@&55>") {
+		t.Errorf("welcome lacks the /link prompt: %q", got)
+	}
+
+	got = rosterMessage(s, "KlutzCo", "guildan", "199", true)
+	if strings.Contains(got, "/link") || !strings.Contains(got, "<@199>") {
+		t.Errorf("linked welcome = %q, want mention without prompt", got)
+	}
+
+	s.FarewellMessage = "So long {member}, {clan} will miss you"
+	if got := rosterMessage(s, "KlutzCo", "leaver", "", false); got != "So long **leaver**, KlutzCo will miss you" {
+		t.Errorf("farewell = %q", got)
+	}
+}
+
+func TestHandleRosterChangesRecordsDates(t *testing.T) {
+	b := &Bot{db: newTestDB(t), logger: logging.Discard()}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// no guild has welcome posts set up, so only the dates are recorded
+	b.handleRosterChanges([]model.ClanMessage{
+		{Message: "newbie joined the clan.", Timestamp: now.Add(-time.Hour)},
+		{Message: "oldie left the clan.", Timestamp: now.Add(-48 * time.Hour)},
+		{Message: "newbie added 5x Gold.", Timestamp: now},
+	}, now)
+
+	newbie, _, err := model.GetMember(b.db, "newbie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !newbie.InClan() || !newbie.JoinedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("newbie = %+v, want joined an hour ago", newbie)
+	}
+	oldie, _, _ := model.GetMember(b.db, "oldie")
+	if !oldie.LeftAt.Equal(now.Add(-48 * time.Hour)) {
+		t.Errorf("oldie = %+v, want left two days ago", oldie)
+	}
+}
@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
+)
+
+// default values
+const (
+	defaultShutdownTimeout   = 30 * time.Second
+	defaultRestartMinBackoff = time.Second
+	defaultRestartMaxBackoff = 5 * time.Minute
+	// a worker that ran this long before crashing restarts with the minimum backoff again
+	restartBackoffReset = 10 * time.Minute
+)
+
+// supervisor owns the bot's background goroutines. It restarts workers that panic or
+// return early with exponential backoff, and lets shutdown wait for all of them to finish.
+type supervisor struct {
+	logger     *slog.Logger
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	wg         sync.WaitGroup
+}
+
+func newSupervisor(logger *slog.Logger) *supervisor {
+	return &supervisor{
+		logger:     logger,
+		minBackoff: defaultRestartMinBackoff,
+		maxBackoff: defaultRestartMaxBackoff,
+	}
+}
+
+// Go runs fn in a supervised goroutine until ctx is cancelled.
+// fn is expected to return only once ctx is done; any earlier return or panic restarts it.
+func (s *supervisor) Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		logger := s.logger.With("worker", name)
+		backoff := s.minBackoff
+
+		for {
+			started := time.Now()
+			err := runWorker(ctx, fn)
+			if ctx.Err() != nil {
+				return
+			}
+
+			if time.Since(started) >= restartBackoffReset {
+				backoff = s.minBackoff
+			}
+			metrics.WorkerRestarts.Inc(name)
+			if err != nil {
+				logger.Error("worker crashed, restarting", logging.Err(err), "backoff", backoff)
+			} else {
+				logger.Warn("worker exited early, restarting", "backoff", backoff)
+			}
+
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			backoff = min(backoff*2, s.maxBackoff)
+		}
+	}()
+}
+
+// runWorker calls fn, converting a panic into an error.
+func runWorker(ctx context.Context, fn func(ctx context.Context)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	fn(ctx)
+	return nil
+}
+
+// Wait blocks until every worker has returned or timeout elapses.
+// It reports whether all workers finished in time.
+func (s *supervisor) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
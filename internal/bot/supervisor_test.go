@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+)
+
+func newTestSupervisor() *supervisor {
+	s := newSupervisor(logging.Discard())
+	s.minBackoff = time.Millisecond
+	s.maxBackoff = 5 * time.Millisecond
+	return s
+}
+
+func TestSupervisorRestartsCrashedWorker(t *testing.T) {
+	s := newTestSupervisor()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs atomic.Int32
+	s.Go(ctx, "crashy", func(ctx context.Context) {
+		if runs.Add(1) < 3 {
+			panic("boom")
+		}
+		<-ctx.Done()
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := runs.Load(); got != 3 {
+		t.Fatalf("worker ran %d times, want 3", got)
+	}
+
+	cancel()
+	if !s.Wait(time.Second) {
+		t.Error("Wait timed out after cancel")
+	}
+}
+
+func TestSupervisorRestartsWorkerThatExitsEarly(t *testing.T) {
+	s := newTestSupervisor()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs atomic.Int32
+	s.Go(ctx, "quitter", func(ctx context.Context) {
+		if runs.Add(1) == 1 {
+			return
+		}
+		<-ctx.Done()
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runs.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := runs.Load(); got != 2 {
+		t.Fatalf("worker ran %d times, want 2", got)
+	}
+	cancel()
+	s.Wait(time.Second)
+}
+
+func TestSupervisorWaitsForInFlightWork(t *testing.T) {
+	s := newTestSupervisor()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var finished atomic.Bool
+	s.Go(ctx, "writer", func(ctx context.Context) {
+		<-ctx.Done()
+		// simulate a write that completes after cancellation
+		time.Sleep(20 * time.Millisecond)
+		finished.Store(true)
+	})
+
+	cancel()
+	if !s.Wait(time.Second) {
+		t.Fatal("Wait timed out")
+	}
+	if !finished.Load() {
+		t.Error("Wait returned before the worker finished its in-flight work")
+	}
+}
+
+func TestSupervisorWaitTimeout(t *testing.T) {
+	s := newTestSupervisor()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+	s.Go(ctx, "stuck", func(ctx context.Context) { <-release })
+
+	cancel()
+	if s.Wait(10 * time.Millisecond) {
+		t.Error("Wait reported success while a worker was still running")
+	}
+}
@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/metrics"
+	"klutco-lil-helper/internal/model"
+)
+
+// runXPSnapshots snapshots every linked member's skill XP on startup and then daily at the given Eastern time.
+func (b *Bot) runXPSnapshots(ctx context.Context, hour, minute int) {
+	logger := b.logFor("xpsnapshot")
+	b.snapshotXP(ctx)
+
+	for {
+		next := nextEasternTime(time.Now(), hour, minute)
+		wait := time.Until(next)
+		logger.Info("next snapshot scheduled", "at", next.Format(time.RFC3339), "in", wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Info("context cancelled, stopping")
+			return
+		case <-timer.C:
+		}
+
+		b.snapshotXP(ctx)
+	}
+}
+
+// snapshotXP stores today's skill XP of every linked member and celebrates the level milestones
+// reached since their previous snapshot. Snapshots are keyed by the Eastern date, so running
+// again the same day just refreshes them.
+func (b *Bot) snapshotXP(ctx context.Context) {
+	defer metrics.JobDuration.ObserveDuration("xpsnapshot", time.Now())
+
+	logger := b.logFor("xpsnapshot")
+	if b.db == nil {
+		logger.Warn("no db available")
+		return
+	}
+
+	linked, err := model.ListLinkedMembers(b.db)
+	if err != nil {
+		logger.Error("failed to list linked members", logging.Err(err))
+		return
+	}
+	names := make([]string, 0, len(linked))
+	for name := range linked {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	day := time.Now().In(easternLocation(logger))
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+
+	saved := 0
+	for _, name := range names {
+		if ctx.Err() != nil {
+			return
+		}
+		player, err := idleclans.Default.PlayerProfile(ctx, name)
+		if err != nil {
+			logger.Warn("failed to fetch player profile", "player", name, logging.Err(err))
+			continue
+		}
+		// compare against the latest snapshot, which is today's on a rerun, so milestones are announced once
+		previous, _, err := model.LatestXPSnapshot(b.db, name)
+		if err != nil {
+			logger.Error("failed to load previous xp snapshot", "player", name, logging.Err(err))
+			continue
+		}
+		if err := model.SaveXPSnapshot(b.db, name, day, player.SkillExperiences); err != nil {
+			logger.Error("failed to save xp snapshot", "player", name, logging.Err(err))
+			continue
+		}
+		saved++
+
+		if previous != nil {
+			b.announceMilestones(name, linked[name], idleclans.Milestones(previous, player.SkillExperiences), logger)
+		}
+	}
+	logger.Info("saved xp snapshots", "players", saved, "linked", len(names))
+}
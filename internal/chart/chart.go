@@ -0,0 +1,303 @@
+// Package chart renders simple line and bar charts as PNG images using only the standard library.
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+)
+
+// Colors matching Discord's dark theme.
+var (
+	Background = color.RGBA{0x2B, 0x2D, 0x31, 0xFF}
+	GridColor  = color.RGBA{0x40, 0x43, 0x49, 0xFF}
+	TextColor  = color.RGBA{0xDB, 0xDE, 0xE1, 0xFF}
+	Green      = color.RGBA{0x57, 0xF2, 0x87, 0xFF}
+	Blue       = color.RGBA{0x58, 0x65, 0xF2, 0xFF}
+	Gold       = color.RGBA{0xFF, 0xD7, 0x00, 0xFF}
+	Red        = color.RGBA{0xED, 0x42, 0x45, 0xFF}
+)
+
+// ErrNoData is returned when there is nothing to plot.
+var ErrNoData = errors.New("chart: no data")
+
+const (
+	textScale = 2
+	padding   = 12
+	gridLines = 4
+)
+
+// Point is a single (x, y) observation. For time series X is usually a Unix timestamp.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Series is a line drawn on a line chart.
+type Series struct {
+	Points []Point
+	Color  color.Color
+}
+
+// Bar is a single labelled bar of a bar chart.
+type Bar struct {
+	Label string // short label drawn under the bar, may be empty
+	Value float64
+	Color color.Color
+}
+
+// Options control the chart size and axis label formatting.
+type Options struct {
+	Width  int
+	Height int
+	XLabel func(x float64) string // formats X axis labels; defaults to FormatNumber
+	YLabel func(y float64) string // formats Y axis labels; defaults to FormatNumber
+}
+
+func (o Options) withDefaults() Options {
+	if o.Width <= 0 {
+		o.Width = 800
+	}
+	if o.Height <= 0 {
+		o.Height = 400
+	}
+	if o.XLabel == nil {
+		o.XLabel = FormatNumber
+	}
+	if o.YLabel == nil {
+		o.YLabel = FormatNumber
+	}
+	return o
+}
+
+// Line renders one or more series as a line chart.
+func Line(opts Options, series ...Series) ([]byte, error) {
+	opts = opts.withDefaults()
+
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, p := range s.Points {
+			minX, maxX = math.Min(minX, p.X), math.Max(maxX, p.X)
+			minY, maxY = math.Min(minY, p.Y), math.Max(maxY, p.Y)
+		}
+	}
+	if math.IsInf(minX, 1) {
+		return nil, ErrNoData
+	}
+	minY, maxY = niceRange(minY, maxY)
+
+	img, plot := newCanvas(opts, minY, maxY)
+
+	// X axis labels at both ends
+	labelY := plot.Max.Y + padding/2
+	drawText(img, plot.Min.X, labelY, opts.XLabel(minX), textScale, TextColor)
+	if maxX > minX {
+		right := opts.XLabel(maxX)
+		drawText(img, plot.Max.X-textWidth(right, textScale), labelY, right, textScale, TextColor)
+	}
+
+	scaleX := func(x float64) int {
+		if maxX == minX {
+			return (plot.Min.X + plot.Max.X) / 2
+		}
+		return plot.Min.X + int(math.Round((x-minX)/(maxX-minX)*float64(plot.Dx())))
+	}
+	scaleY := func(y float64) int {
+		return plot.Max.Y - int(math.Round((y-minY)/(maxY-minY)*float64(plot.Dy())))
+	}
+
+	for _, s := range series {
+		c := s.Color
+		if c == nil {
+			c = Green
+		}
+		for i, p := range s.Points {
+			x, y := scaleX(p.X), scaleY(p.Y)
+			if i == 0 {
+				fillRect(img, x-1, y-1, 3, 3, c)
+				continue
+			}
+			prev := s.Points[i-1]
+			drawLine(img, scaleX(prev.X), scaleY(prev.Y), x, y, c)
+		}
+	}
+
+	return encode(img)
+}
+
+// BarChart renders a bar chart with one bar per entry.
+func BarChart(opts Options, bars []Bar) ([]byte, error) {
+	opts = opts.withDefaults()
+	if len(bars) == 0 {
+		return nil, ErrNoData
+	}
+
+	minY, maxY := 0.0, math.Inf(-1)
+	for _, b := range bars {
+		minY, maxY = math.Min(minY, b.Value), math.Max(maxY, b.Value)
+	}
+	minY, maxY = niceRange(minY, maxY)
+
+	img, plot := newCanvas(opts, minY, maxY)
+
+	scaleY := func(y float64) int {
+		return plot.Max.Y - int(math.Round((y-minY)/(maxY-minY)*float64(plot.Dy())))
+	}
+	zero := scaleY(0)
+
+	slot := plot.Dx() / len(bars)
+	barWidth := slot * 3 / 4
+	if barWidth < 1 {
+		barWidth = 1
+	}
+	for i, b := range bars {
+		c := b.Color
+		if c == nil {
+			c = Blue
+		}
+		x := plot.Min.X + i*slot + (slot-barWidth)/2
+		top, bottom := scaleY(b.Value), zero
+		if top > bottom {
+			top, bottom = bottom, top
+		}
+		fillRect(img, x, top, barWidth, bottom-top+1, c)
+
+		if b.Label != "" && textWidth(b.Label, textScale) <= slot {
+			drawText(img, x+(barWidth-textWidth(b.Label, textScale))/2, plot.Max.Y+padding/2, b.Label, textScale, TextColor)
+		}
+	}
+
+	return encode(img)
+}
+
+// newCanvas paints the background, horizontal grid lines and Y axis labels,
+// and returns the rectangle left for plotting.
+func newCanvas(opts Options, minY, maxY float64) (*image.RGBA, image.Rectangle) {
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	fillRect(img, 0, 0, opts.Width, opts.Height, Background)
+
+	labelWidth := 0
+	for i := 0; i <= gridLines; i++ {
+		y := minY + (maxY-minY)*float64(i)/gridLines
+		if w := textWidth(opts.YLabel(y), textScale); w > labelWidth {
+			labelWidth = w
+		}
+	}
+
+	textHeight := glyphHeight * textScale
+	plot := image.Rect(
+		padding+labelWidth+padding/2,
+		padding+textHeight/2,
+		opts.Width-padding,
+		opts.Height-padding-textHeight-padding/2,
+	)
+
+	for i := 0; i <= gridLines; i++ {
+		value := minY + (maxY-minY)*float64(i)/gridLines
+		y := plot.Max.Y - plot.Dy()*i/gridLines
+		fillRect(img, plot.Min.X, y, plot.Dx(), 1, GridColor)
+
+		label := opts.YLabel(value)
+		drawText(img, plot.Min.X-padding/2-textWidth(label, textScale), y-textHeight/2, label, textScale, TextColor)
+	}
+
+	return img, plot
+}
+
+// niceRange widens [lo, hi] slightly so lines do not hug the edges and a flat series still has height.
+func niceRange(lo, hi float64) (float64, float64) {
+	if hi == lo {
+		if lo == 0 {
+			return 0, 1
+		}
+		return lo - math.Abs(lo)*0.1, hi + math.Abs(hi)*0.1
+	}
+	margin := (hi - lo) * 0.05
+	if lo >= 0 && lo-margin < 0 {
+		return 0, hi + margin
+	}
+	return lo - margin, hi + margin
+}
+
+// FormatNumber formats a value compactly for axis labels, e.g. 1500 -> "1.5k".
+func FormatNumber(v float64) string {
+	abs := math.Abs(v)
+	switch {
+	case abs >= 1e9:
+		return trimZero(fmt.Sprintf("%.1f", v/1e9)) + "B"
+	case abs >= 1e6:
+		return trimZero(fmt.Sprintf("%.1f", v/1e6)) + "M"
+	case abs >= 1e3:
+		return trimZero(fmt.Sprintf("%.1f", v/1e3)) + "k"
+	case abs >= 10 || v == 0:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return trimZero(fmt.Sprintf("%.1f", v))
+	}
+}
+
+func trimZero(s string) string {
+	if len(s) > 2 && s[len(s)-2:] == ".0" {
+		return s[:len(s)-2]
+	}
+	return s
+}
+
+func encode(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fillRect paints a w x h rectangle, clipped to the image bounds.
+func fillRect(img *image.RGBA, x, y, w, h int, c color.Color) {
+	r := image.Rect(x, y, x+w, y+h).Intersect(img.Bounds())
+	for py := r.Min.Y; py < r.Max.Y; py++ {
+		for px := r.Min.X; px < r.Max.X; px++ {
+			img.Set(px, py, c)
+		}
+	}
+}
+
+// drawLine draws a 2px thick line using Bresenham's algorithm.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		fillRect(img, x0, y0, 2, 2, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
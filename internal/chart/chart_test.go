@@ -0,0 +1,86 @@
+package chart
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"testing"
+)
+
+func TestLine(t *testing.T) {
+	data, err := Line(Options{Width: 320, Height: 160},
+		Series{Points: []Point{{0, 10}, {1, 25}, {2, 15}}, Color: Green},
+		Series{Points: []Point{{0, 12}, {2, 12}}, Color: Gold},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("output is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 320 || b.Dy() != 160 {
+		t.Errorf("size = %dx%d, want 320x160", b.Dx(), b.Dy())
+	}
+
+	// the first point of the green series is drawn at the left edge of the plot area
+	found := false
+	for y := 0; y < 160 && !found; y++ {
+		for x := 0; x < 320; x++ {
+			if r, g, b, _ := img.At(x, y).RGBA(); r>>8 == uint32(Green.R) && g>>8 == uint32(Green.G) && b>>8 == uint32(Green.B) {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		t.Error("no pixel of the series color was drawn")
+	}
+}
+
+func TestLineSinglePoint(t *testing.T) {
+	if _, err := Line(Options{}, Series{Points: []Point{{5, 5}}}); err != nil {
+		t.Errorf("single point: %v", err)
+	}
+}
+
+func TestNoData(t *testing.T) {
+	if _, err := Line(Options{}); !errors.Is(err, ErrNoData) {
+		t.Errorf("Line() err = %v, want ErrNoData", err)
+	}
+	if _, err := BarChart(Options{}, nil); !errors.Is(err, ErrNoData) {
+		t.Errorf("BarChart() err = %v, want ErrNoData", err)
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	data, err := BarChart(Options{Width: 200, Height: 100}, []Bar{
+		{Label: "1", Value: 3},
+		{Label: "2", Value: -1},
+		{Label: "3", Value: 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		t.Fatalf("output is not a PNG: %v", err)
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := map[float64]string{
+		0:          "0",
+		5.5:        "5.5",
+		42:         "42",
+		1500:       "1.5k",
+		2000000:    "2M",
+		-3400:      "-3.4k",
+		7100000000: "7.1B",
+	}
+	for in, want := range tests {
+		if got := FormatNumber(in); got != want {
+			t.Errorf("FormatNumber(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
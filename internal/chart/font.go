@@ -0,0 +1,66 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+)
+
+// glyphWidth and glyphHeight are the size of a glyph in font pixels before scaling.
+const (
+	glyphWidth  = 3
+	glyphHeight = 5
+)
+
+// glyphs is a tiny 3x5 bitmap font covering what axis labels need.
+// Each string is a row, '#' marks a lit pixel.
+var glyphs = map[rune][glyphHeight]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'.': {"...", "...", "...", "...", ".#."},
+	',': {"...", "...", "...", ".#.", "#.."},
+	'-': {"...", "...", "###", "...", "..."},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+	'/': {"..#", "..#", ".#.", "#..", "#.."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	'%': {"#.#", "..#", ".#.", "#..", "#.#"},
+	'k': {"#..", "#.#", "##.", "#.#", "#.#"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'd': {"..#", "..#", "###", "#.#", "###"},
+	'h': {"#..", "#..", "###", "#.#", "#.#"},
+	'g': {"###", "#.#", "###", "..#", "##."},
+}
+
+// textWidth returns the width in pixels of s drawn at the given scale.
+func textWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return (n*(glyphWidth+1) - 1) * scale
+}
+
+// drawText draws s with its top-left corner at (x, y). Unknown runes render as blanks.
+func drawText(img *image.RGBA, x, y int, s string, scale int, c color.Color) {
+	for _, r := range s {
+		if g, ok := glyphs[r]; ok {
+			for row := 0; row < glyphHeight; row++ {
+				for col := 0; col < glyphWidth; col++ {
+					if g[row][col] != '#' {
+						continue
+					}
+					fillRect(img, x+col*scale, y+row*scale, scale, scale, c)
+				}
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
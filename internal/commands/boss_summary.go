@@ -1,10 +1,10 @@
 package commands
 
 import (
-	"log"
 	"os"
 
 	"klutco-lil-helper/internal/bosssummary"
+	"klutco-lil-helper/internal/logging"
 	"klutco-lil-helper/internal/model"
 
 	"github.com/bwmarrin/discordgo"
@@ -31,7 +31,7 @@ func bossSummaryHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		},
 	})
 	if err != nil {
-		log.Printf("[boss_summary] failed to acknowledge interaction: %v", err)
+		interactionLogger(i).Error("failed to acknowledge interaction", logging.Err(err))
 		return
 	}
 
@@ -58,7 +58,7 @@ func bossSummaryHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 
 	if summaryChannelID == "" {
-		log.Printf("[boss_summary] summary channel %q not found", summaryChannelName)
+		interactionLogger(i).Warn("summary channel not found", "channel_name", summaryChannelName)
 		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 			Content: strPtr("Failed to find summary channel."),
 		})
@@ -68,7 +68,7 @@ func bossSummaryHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	// Check if there's an existing boss summary message in the summary channel
 	summaryMsgID, err := model.GetScheduledMessage(DB, model.MessageTypeBossSummary, summaryChannelID)
 	if err != nil {
-		log.Printf("[boss_summary] failed to get scheduled message: %v", err)
+		interactionLogger(i).Error("failed to get scheduled message", logging.Err(err))
 		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 			Content: strPtr("Failed to check for existing summary message."),
 		})
@@ -84,9 +84,9 @@ func bossSummaryHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 
 	// Regenerate the summary in the configured summary channel
-	err = bosssummary.RegenerateSummary(s, DB, summaryChannelID)
+	err = bosssummary.RegenerateSummary(s, DB, summaryChannelID, Logger.With(logging.KeySubsystem, "bosssummary"))
 	if err != nil {
-		log.Printf("[boss_summary] failed to regenerate summary: %v", err)
+		interactionLogger(i).Error("failed to regenerate summary", logging.Err(err))
 		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
 			Content: strPtr("Failed to regenerate boss summary."),
 		})
@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"bytes"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// attachChart shows a rendered PNG chart as the embed's image.
+// The returned files must be sent alongside the embed, e.g. in InteractionResponseData.Files.
+func attachChart(embed *discordgo.MessageEmbed, filename string, png []byte) []*discordgo.File {
+	embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + filename}
+	return []*discordgo.File{
+		{
+			Name:        filename,
+			ContentType: "image/png",
+			Reader:      bytes.NewReader(png),
+		},
+	}
+}
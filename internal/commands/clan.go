@@ -0,0 +1,281 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+	"klutco-lil-helper/internal/numfmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// clanTopContributors is how many vault contributors /clan lists.
+	clanTopContributors = 5
+	// clanVaultKeysShown is how many kinds of keys the vault highlights list.
+	clanVaultKeysShown = 4
+)
+
+var clanCommand = &discordgo.ApplicationCommand{
+	Name:        "clan",
+	Description: "Show an overview of the clan",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "name",
+			Description: "Another clan to look up and compare with ours",
+			Required:    false,
+		},
+	},
+}
+
+// clanOverview is everything /clan shows. Vault data only exists for our own clan.
+type clanOverview struct {
+	Clan      *idleclans.Clan
+	Own       bool
+	Vault     model.VaultSummary
+	VaultKeys map[string]int
+	Ours      *idleclans.Clan // our clan, for comparison when another clan was asked for
+}
+
+func clanHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "clan" {
+		return
+	}
+
+	name := model.ClanName
+	if o, ok := optionMap(i.ApplicationCommandData().Options)["name"]; ok && strings.TrimSpace(o.StringValue()) != "" {
+		name = strings.TrimSpace(o.StringValue())
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		interactionLogger(i).Error("failed to acknowledge interaction", logging.Err(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	overview, err := loadClanOverview(ctx, name, time.Now())
+	if err != nil {
+		content := "❌ Failed to load the clan."
+		if errors.Is(err, idleclans.ErrNotFound) {
+			content = fmt.Sprintf("No clan named **%s**.", name)
+		} else {
+			interactionLogger(i).Error("failed to load clan overview", "clan", name, logging.Err(err))
+		}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: strPtr(content),
+		})
+		return
+	}
+
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{formatClanEmbed(overview)},
+	})
+}
+
+// loadClanOverview fetches a clan and, for our own clan, this week's vault activity.
+func loadClanOverview(ctx context.Context, name string, now time.Time) (clanOverview, error) {
+	clan, err := idleclans.Default.ClanInfo(ctx, name)
+	if err != nil {
+		return clanOverview{}, err
+	}
+	overview := clanOverview{Clan: clan, Own: strings.EqualFold(clan.Name, model.ClanName)}
+
+	if !overview.Own {
+		// the comparison is a bonus; show the other clan alone if ours can't be loaded
+		if ours, err := idleclans.Default.ClanInfo(ctx, model.ClanName); err == nil {
+			overview.Ours = ours
+		}
+		return overview, nil
+	}
+
+	if overview.Vault, err = model.SummarizeVault(DB, now.AddDate(0, 0, -7)); err != nil {
+		return clanOverview{}, fmt.Errorf("summarize vault: %w", err)
+	}
+	if overview.VaultKeys, err = model.GetKeyCounts(DB, model.KeyVaultHolder); err != nil {
+		return clanOverview{}, fmt.Errorf("load vault keys: %w", err)
+	}
+	return overview, nil
+}
+
+// formatClanEmbed renders the clan overview.
+func formatClanEmbed(o clanOverview) *discordgo.MessageEmbed {
+	c := o.Clan
+	embed := &discordgo.MessageEmbed{
+		Title:       c.Name,
+		Description: clanSummaryLine(c),
+		Color:       0x5865F2, // Blurple
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Members", Value: formatClanMembers(c), Inline: true},
+			{Name: "Recruitment", Value: formatRecruitment(c), Inline: true},
+			{Name: "Upgrades", Value: formatUpgrades(c), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Data from Idle Clans clan API",
+		},
+	}
+
+	if o.Own {
+		embed.Fields = append(embed.Fields,
+			&discordgo.MessageEmbedField{Name: "Vault this week", Value: formatVaultHighlights(o.Vault, o.VaultKeys)},
+			&discordgo.MessageEmbedField{Name: "Top contributors this week", Value: formatTopContributors(o.Vault.Contributors)},
+		)
+		embed.Footer.Text += " and clan logs"
+	}
+
+	if o.Ours != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Compared with " + o.Ours.Name,
+			Value: formatClanComparison(c, o.Ours),
+		})
+	}
+	return embed
+}
+
+func clanSummaryLine(c *idleclans.Clan) string {
+	var parts []string
+	if c.Tag != "" {
+		parts = append(parts, "["+c.Tag+"]")
+	}
+	for _, p := range []string{c.Category, c.Language} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	line := strings.Join(parts, " · ")
+	if msg := strings.TrimSpace(c.RecruitmentMessage); msg != "" {
+		if line != "" {
+			line += "\n"
+		}
+		line += "> " + truncate(msg, 200)
+	}
+	return line
+}
+
+func formatClanMembers(c *idleclans.Clan) string {
+	counts := c.RankCounts()
+	ranks := make([]idleclans.Rank, 0, len(counts))
+	for r := range counts {
+		ranks = append(ranks, r)
+	}
+	sort.Slice(ranks, func(i, j int) bool { return ranks[i] > ranks[j] })
+
+	var parts []string
+	for _, r := range ranks {
+		label := strings.ToLower(r.String())
+		if counts[r] != 1 {
+			label += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", counts[r], label))
+	}
+	value := strconv.Itoa(len(c.Members))
+	if len(parts) > 0 {
+		value += "\n" + strings.Join(parts, ", ")
+	}
+	return value
+}
+
+func formatRecruitment(c *idleclans.Clan) string {
+	status := "🔒 Closed"
+	if c.IsRecruiting {
+		status = "✅ Open"
+	}
+	if c.MinimumTotalLevel > 0 {
+		status += fmt.Sprintf("\nMin. total level %d", c.MinimumTotalLevel)
+	}
+	return status
+}
+
+func formatUpgrades(c *idleclans.Clan) string {
+	if n := c.UpgradeCount(); n >= 0 {
+		return fmt.Sprintf("%d unlocked", n)
+	}
+	return "—"
+}
+
+func formatVaultHighlights(v model.VaultSummary, keys map[string]int) string {
+	lines := []string{fmt.Sprintf("💰 %s gold in, %s out", numfmt.Thousands(v.GoldDeposited), numfmt.Thousands(v.GoldWithdrawn))}
+	if v.ItemsDeposited > 0 {
+		lines = append(lines, fmt.Sprintf("📦 %s items deposited", numfmt.Thousands(v.ItemsDeposited)))
+	}
+
+	type keyCount struct {
+		name  string
+		count int
+	}
+	var held []keyCount
+	for name, n := range keys {
+		if n > 0 {
+			held = append(held, keyCount{name, n})
+		}
+	}
+	sort.Slice(held, func(i, j int) bool {
+		if held[i].count != held[j].count {
+			return held[i].count > held[j].count
+		}
+		return held[i].name < held[j].name
+	})
+	if len(held) > clanVaultKeysShown {
+		held = held[:clanVaultKeysShown]
+	}
+	if len(held) > 0 {
+		parts := make([]string, 0, len(held))
+		for _, k := range held {
+			parts = append(parts, fmt.Sprintf("%s ×%d", titleizer.String(k.name), k.count))
+		}
+		lines = append(lines, "🗝️ "+strings.Join(parts, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatTopContributors(contributors []model.VaultContribution) string {
+	if len(contributors) == 0 {
+		return "No deposits yet this week."
+	}
+	if len(contributors) > clanTopContributors {
+		contributors = contributors[:clanTopContributors]
+	}
+
+	medals := []string{"🥇", "🥈", "🥉"}
+	lines := make([]string, 0, len(contributors))
+	for i, c := range contributors {
+		place := fmt.Sprintf("%d.", i+1)
+		if i < len(medals) {
+			place = medals[i]
+		}
+		var parts []string
+		if c.Gold > 0 {
+			parts = append(parts, numfmt.Thousands(c.Gold)+" gold")
+		}
+		if c.Items > 0 {
+			parts = append(parts, numfmt.Thousands(c.Items)+" items")
+		}
+		lines = append(lines, fmt.Sprintf("%s **%s** — %s", place, c.Player, strings.Join(parts, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatClanComparison(c, ours *idleclans.Clan) string {
+	lines := []string{fmt.Sprintf("Members: %d vs %d", len(c.Members), len(ours.Members))}
+	if a, b := c.UpgradeCount(), ours.UpgradeCount(); a >= 0 && b >= 0 {
+		lines = append(lines, fmt.Sprintf("Upgrades: %d vs %d", a, b))
+	}
+	lines = append(lines, fmt.Sprintf("Min. total level: %d vs %d", c.MinimumTotalLevel, ours.MinimumTotalLevel))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/model"
+)
+
+func TestFormatClanEmbed(t *testing.T) {
+	clan := &idleclans.Clan{
+		Name:               "KlutzCo",
+		Tag:                "KLZ",
+		IsRecruiting:       true,
+		MinimumTotalLevel:  500,
+		SerializedUpgrades: "[1,2,3]",
+		Members: []idleclans.ClanMember{
+			{Name: "ImaKlutz", Rank: idleclans.RankLeader},
+			{Name: "guildan", Rank: idleclans.RankMember},
+			{Name: "moraxam", Rank: idleclans.RankMember},
+		},
+	}
+	vault := model.VaultSummary{
+		GoldDeposited: 1005000,
+		Contributors: []model.VaultContribution{
+			{Player: "guildan", Gold: 1000000, Items: 2},
+			{Player: "moraxam", Gold: 5000},
+		},
+	}
+
+	embed := formatClanEmbed(clanOverview{Clan: clan, Own: true, Vault: vault, VaultKeys: map[string]int{"godly": 3, "stone": 0}})
+
+	fields := map[string]string{}
+	for _, f := range embed.Fields {
+		fields[f.Name] = f.Value
+	}
+	checks := map[string]string{
+		"Members":                    "3\n1 leader, 2 members",
+		"Recruitment":                "✅ Open\nMin. total level 500",
+		"Upgrades":                   "3 unlocked",
+		"Vault this week":            "💰 1,005,000 gold in, 0 out\n🗝️ Godly ×3",
+		"Top contributors this week": "🥇 **guildan** — 1,000,000 gold, 2 items\n🥈 **moraxam** — 5,000 gold",
+	}
+	for name, want := range checks {
+		if fields[name] != want {
+			t.Errorf("%s = %q, want %q", name, fields[name], want)
+		}
+	}
+
+	other := &idleclans.Clan{Name: "Rivals", Members: make([]idleclans.ClanMember, 7)}
+	embed = formatClanEmbed(clanOverview{Clan: other, Ours: clan})
+	last := embed.Fields[len(embed.Fields)-1]
+	if last.Name != "Compared with KlutzCo" || !strings.Contains(last.Value, "Members: 7 vs 3") {
+		t.Errorf("comparison field = %+v", last)
+	}
+	for _, f := range embed.Fields {
+		if strings.HasPrefix(f.Name, "Vault") {
+			t.Error("another clan's overview shows our vault")
+		}
+	}
+}
@@ -1,7 +1,8 @@
 package commands
 
 import (
-	"log"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -10,8 +11,32 @@ func RegisterCommands(s *discordgo.Session, appId string) {
 	// Register slash commands
 	registerCommand(s, bossCommand, appId)
 	registerCommand(s, keysCommand, appId)
+	registerCommand(s, keyPoolCommand, appId)
 	registerCommand(s, marketFoodCommand, appId)
 	registerCommand(s, bossSummaryCommand, appId)
+	registerCommand(s, priceCommand, appId)
+	registerCommand(s, priceAlertCommand, appId)
+	// /cook-profit needs recipes whose ingredients have market IDs, which only a served
+	// catalog provides; without one the command is not offered at all
+	if cmd, ok := cookProfitCommandFor(market.DefaultCatalog()); ok {
+		registerCommand(s, cmd, appId)
+	} else {
+		Logger.Warn("no cooking recipe in the item catalog can be priced, /cook-profit is disabled; serve the ingredients through ITEM_CATALOG_URL")
+		unregisterCommand(s, cookProfitCommand.Name, appId)
+	}
+	registerCommand(s, priceHistoryCommand, appId)
+	registerCommand(s, marketDealsCommand, appId)
+	registerCommand(s, relayCommand, appId)
+	registerCommand(s, linkCommand, appId)
+	registerCommand(s, linksCommand, appId)
+	registerCommand(s, welcomeCommand, appId)
+	registerCommand(s, ranksCommand, appId)
+	registerCommand(s, inactiveCommand, appId)
+	registerCommand(s, playerCommand, appId)
+	registerCommand(s, clanCommand, appId)
+	registerCommand(s, xpCommand, appId)
+	registerCommand(s, milestonesCommand, appId)
+	registerCommand(s, digestCommand, appId)
 
 	// Register handlers
 	s.AddHandler(bossHandler)
@@ -19,16 +44,71 @@ func RegisterCommands(s *discordgo.Session, appId string) {
 
 	s.AddHandler(keysHandler)
 	s.AddHandler(keysAutocompleteHandler)
+	s.AddHandler(keyPoolHandler)
 
 	s.AddHandler(marketFoodHandler)
 
 	s.AddHandler(bossSummaryHandler)
+
+	s.AddHandler(priceHandler)
+	s.AddHandler(priceAutocompleteHandler)
+
+	s.AddHandler(priceAlertHandler)
+	s.AddHandler(priceAlertAutocompleteHandler)
+
+	s.AddHandler(cookProfitHandler)
+
+	s.AddHandler(priceHistoryHandler)
+	s.AddHandler(priceHistoryAutocompleteHandler)
+
+	s.AddHandler(marketDealsHandler)
+	s.AddHandler(marketDealsAutocompleteHandler)
+
+	s.AddHandler(relayHandler)
+
+	s.AddHandler(linkHandler)
+	s.AddHandler(linksHandler)
+
+	s.AddHandler(welcomeHandler)
+
+	s.AddHandler(ranksHandler)
+
+	s.AddHandler(inactiveHandler)
+
+	s.AddHandler(playerHandler)
+	s.AddHandler(playerAutocompleteHandler)
+
+	s.AddHandler(clanHandler)
+
+	s.AddHandler(xpHandler)
+	s.AddHandler(xpAutocompleteHandler)
+
+	s.AddHandler(milestonesHandler)
+
+	s.AddHandler(digestHandler)
 }
 
 func registerCommand(s *discordgo.Session, cmd *discordgo.ApplicationCommand, appId string) {
 	_, err := s.ApplicationCommandCreate(appId, "", cmd)
 	if err != nil {
-		log.Printf("Failed to register command %s: %v", cmd.Name, err)
+		Logger.Error("failed to register command", "command", cmd.Name, logging.Err(err))
+	}
+}
+
+// unregisterCommand removes a command registered by an earlier run.
+func unregisterCommand(s *discordgo.Session, name string, appId string) {
+	cmds, err := s.ApplicationCommands(appId, "")
+	if err != nil {
+		Logger.Error("failed to list commands", logging.Err(err))
+		return
+	}
+	for _, cmd := range cmds {
+		if cmd.Name != name {
+			continue
+		}
+		if err := s.ApplicationCommandDelete(appId, "", cmd.ID); err != nil {
+			Logger.Error("failed to unregister command", "command", name, logging.Err(err))
+		}
 	}
 }
 
@@ -39,3 +119,54 @@ func ephemeralFlag(ephemeral bool) discordgo.MessageFlags {
 	}
 	return 0
 }
+
+// respondEphemeral sends a plain text response only visible to the caller.
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// optionMap indexes command options by name.
+func optionMap(options []*discordgo.ApplicationCommandInteractionDataOption) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	m := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, o := range options {
+		m[o.Name] = o
+	}
+	return m
+}
+
+// interactionUserID returns the ID of the user who triggered the interaction,
+// whether it came from a guild (Member) or a DM (User).
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+// focusedValue returns the string value of the focused option, looking inside subcommands.
+func focusedValue(options []*discordgo.ApplicationCommandInteractionDataOption) string {
+	for _, o := range options {
+		if o.Focused {
+			return o.StringValue()
+		}
+		if len(o.Options) > 0 {
+			if v := focusedValue(o.Options); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,255 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// defaultMarketSellTax is the share of every market sale kept by the game.
+// MARKET_SELL_TAX overrides it, e.g. "0.05" for 5%.
+const defaultMarketSellTax = 0.05
+
+// CookingProfit is the margin of crafting one unit of a recipe and selling it.
+type CookingProfit struct {
+	Item          market.Item
+	SellPrice     float64 // lowest sell price of the crafted item
+	IngredientsGP float64 // cost of buying every ingredient
+	Profit        float64 // after tax, per craft
+	ProfitPerHour float64 // 0 when the crafting time is unknown
+}
+
+var cookProfitCommand = &discordgo.ApplicationCommand{
+	Name:        "cook-profit",
+	Description: "Rank cooking recipes by profit using current market prices",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "sort",
+			Description: "Rank by profit per craft (default) or per hour.",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Per craft", Value: "craft"},
+				{Name: "Per hour", Value: "hour"},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "just_for_me",
+			Description: "Only show the results to me.",
+			Required:    false,
+		},
+	},
+}
+
+// cookProfitCommandFor returns the /cook-profit command for a catalog, and false when none of
+// its food recipes can be priced. The per-hour ranking is only offered when recipes have
+// crafting times.
+func cookProfitCommandFor(catalog *market.Catalog) (*discordgo.ApplicationCommand, bool) {
+	priceable, timed := false, false
+	for _, it := range catalog.Craftable() {
+		if !strings.EqualFold(it.Category, market.CategoryFood) || !ingredientsKnown(catalog, it.Recipe) {
+			continue
+		}
+		priceable = true
+		timed = timed || it.Recipe.Seconds > 0
+	}
+	if !priceable {
+		return nil, false
+	}
+	if timed {
+		return cookProfitCommand, true
+	}
+
+	cmd := *cookProfitCommand
+	cmd.Options = nil
+	for _, o := range cookProfitCommand.Options {
+		if o.Name != "sort" {
+			cmd.Options = append(cmd.Options, o)
+		}
+	}
+	return &cmd, true
+}
+
+func cookProfitHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "cook-profit" {
+		return
+	}
+
+	opts := optionMap(i.ApplicationCommandData().Options)
+
+	justForMe := false
+	if o, ok := opts["just_for_me"]; ok {
+		justForMe = o.BoolValue()
+	}
+	perHour := false
+	if o, ok := opts["sort"]; ok {
+		perHour = o.StringValue() == "hour"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	snap, err := market.Prices.Get(ctx)
+	if err != nil {
+		interactionLogger(i).Error("failed to fetch market prices", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to fetch market data. The API may be temporarily unavailable. Please try again later.")
+		return
+	}
+
+	tax := marketSellTax()
+	profits, missing := calculateCookingProfits(market.DefaultCatalog(), snap.LowestSellPrices(), tax)
+	if len(profits) == 0 {
+		respondEphemeral(s, i, "⚠️ No recipe has market prices for both the dish and its ingredients.")
+		return
+	}
+	sortCookingProfits(profits, perHour)
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{formatCookProfitEmbed(profits, missing, tax, marketDataFooter(snap))},
+			Flags:  ephemeralFlag(justForMe),
+		},
+	})
+}
+
+// marketSellTax returns the configured market tax rate.
+func marketSellTax() float64 {
+	if v := os.Getenv("MARKET_SELL_TAX"); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 && f < 1 {
+			return f
+		}
+		Logger.Warn("invalid MARKET_SELL_TAX, using default", "value", v, "default", defaultMarketSellTax)
+	}
+	return defaultMarketSellTax
+}
+
+// calculateCookingProfits prices every craftable food in the catalog.
+// Recipes whose dish or ingredients have no price are returned by name in missing.
+func calculateCookingProfits(catalog *market.Catalog, priceMap map[int]float64, tax float64) ([]CookingProfit, []string) {
+	var profits []CookingProfit
+	var missing []string
+
+	for _, it := range catalog.Craftable() {
+		if !strings.EqualFold(it.Category, market.CategoryFood) {
+			continue
+		}
+
+		sell, ok := priceMap[it.ID]
+		if !ok || sell <= 0 {
+			missing = append(missing, it.Name)
+			continue
+		}
+
+		cost, ok := ingredientsCost(catalog, priceMap, it.Recipe)
+		if !ok {
+			missing = append(missing, it.Name)
+			continue
+		}
+
+		p := CookingProfit{
+			Item:          it,
+			SellPrice:     sell,
+			IngredientsGP: cost,
+			Profit:        sell*(1-tax) - cost,
+		}
+		if it.Recipe.Seconds > 0 {
+			p.ProfitPerHour = p.Profit * 3600 / it.Recipe.Seconds
+		}
+		profits = append(profits, p)
+	}
+
+	sort.Strings(missing)
+	return profits, missing
+}
+
+// ingredientsCost sums the market price of a recipe's ingredients.
+func ingredientsCost(catalog *market.Catalog, priceMap map[int]float64, r *market.Recipe) (float64, bool) {
+	total := 0.0
+	for name, qty := range r.Ingredients {
+		ing, ok := catalog.ByName(name)
+		if !ok {
+			return 0, false
+		}
+		price, ok := priceMap[ing.ID]
+		if !ok || price <= 0 {
+			return 0, false
+		}
+		total += price * float64(qty)
+	}
+	return total, true
+}
+
+// ingredientsKnown reports whether every ingredient of a recipe has a catalog entry.
+func ingredientsKnown(catalog *market.Catalog, r *market.Recipe) bool {
+	for name := range r.Ingredients {
+		if _, ok := catalog.ByName(name); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sortCookingProfits ranks recipes best first, by profit per hour or per craft.
+// Recipes without a known crafting time sink to the bottom of the per-hour ranking.
+func sortCookingProfits(profits []CookingProfit, perHour bool) {
+	sort.SliceStable(profits, func(a, b int) bool {
+		if perHour {
+			ha, hb := profits[a].Item.Recipe.Seconds > 0, profits[b].Item.Recipe.Seconds > 0
+			if ha != hb {
+				return ha
+			}
+			if profits[a].ProfitPerHour != profits[b].ProfitPerHour {
+				return profits[a].ProfitPerHour > profits[b].ProfitPerHour
+			}
+		}
+		return profits[a].Profit > profits[b].Profit
+	})
+}
+
+// formatCookProfitEmbed creates the Discord embed for cooking profits
+func formatCookProfitEmbed(profits []CookingProfit, missing []string, tax float64, footer string) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "Cooking Profit",
+		Description: fmt.Sprintf("Selling price after the %.0f%% market tax, minus ingredients bought at the lowest sell price", tax*100),
+		Color:       0x00FF00, // Green
+		Fields:      []*discordgo.MessageEmbedField{},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: footer,
+		},
+	}
+
+	for idx, p := range profits {
+		if idx >= 25 {
+			break
+		}
+		value := fmt.Sprintf("%+.0f g/craft (sells %.0f g, costs %.0f g)", p.Profit, p.SellPrice, p.IngredientsGP)
+		if p.ProfitPerHour != 0 {
+			value += fmt.Sprintf("\n%+.0f g/hour", p.ProfitPerHour)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   p.Item.DisplayName(),
+			Value:  value,
+			Inline: true,
+		})
+	}
+
+	if len(missing) > 0 {
+		embed.Footer.Text += fmt.Sprintf(" · %d recipe(s) skipped for missing prices", len(missing))
+	}
+
+	return embed
+}
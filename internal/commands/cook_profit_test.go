@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"testing"
+
+	"klutco-lil-helper/internal/market"
+)
+
+func TestCalculateCookingProfits(t *testing.T) {
+	catalog := market.NewCatalog([]market.Item{
+		{ID: 1, Name: "raw_tuna", Category: "fish"},
+		{ID: 2, Name: "cooked_tuna", Category: "food", Healing: 17,
+			Recipe: &market.Recipe{Ingredients: map[string]int{"raw_tuna": 1}, Seconds: 6}},
+		{ID: 3, Name: "potato", Category: "farming"},
+		{ID: 4, Name: "potato_soup", Category: "food", Healing: 5,
+			Recipe: &market.Recipe{Ingredients: map[string]int{"potato": 2, "raw_tuna": 1}}},
+		{ID: 5, Name: "stew", Category: "food", Healing: 19,
+			Recipe: &market.Recipe{Ingredients: map[string]int{"raw_beef": 1}}},
+	})
+	prices := map[int]float64{1: 100, 2: 200, 3: 10, 4: 150, 5: 500}
+
+	profits, missing := calculateCookingProfits(catalog, prices, 0.1)
+
+	if len(missing) != 1 || missing[0] != "stew" {
+		t.Errorf("missing = %v, want [stew]", missing)
+	}
+	if len(profits) != 2 {
+		t.Fatalf("len(profits) = %d, want 2", len(profits))
+	}
+
+	byName := map[string]CookingProfit{}
+	for _, p := range profits {
+		byName[p.Item.Name] = p
+	}
+
+	tuna := byName["cooked_tuna"]
+	if tuna.Profit != 80 { // 200 * 0.9 - 100
+		t.Errorf("cooked_tuna profit = %v, want 80", tuna.Profit)
+	}
+	if tuna.ProfitPerHour != 48000 {
+		t.Errorf("cooked_tuna profit/hour = %v, want 48000", tuna.ProfitPerHour)
+	}
+
+	soup := byName["potato_soup"]
+	if soup.IngredientsGP != 120 || soup.Profit != 15 { // 150 * 0.9 - (2*10 + 100)
+		t.Errorf("potato_soup = %+v, want cost 120 and profit 15", soup)
+	}
+
+	sortCookingProfits(profits, true)
+	if profits[0].Item.Name != "cooked_tuna" {
+		t.Errorf("per-hour ranking starts with %s, want cooked_tuna", profits[0].Item.Name)
+	}
+}
+
+func TestCookProfitCommandFor(t *testing.T) {
+	// the embedded catalog has no ingredient IDs, so the command is not offered
+	embedded := market.DefaultCatalog()
+	if _, ok := cookProfitCommandFor(embedded); ok {
+		t.Error("/cook-profit offered without priceable recipes")
+	}
+
+	// a served catalog that only adds the raw fish makes the embedded recipe priceable,
+	// but without crafting times there is no per-hour ranking
+	catalog := embedded.Merge([]market.Item{{ID: 90001, Name: "raw_tuna", Category: "fish"}})
+	cmd, ok := cookProfitCommandFor(catalog)
+	if !ok {
+		t.Fatal("/cook-profit not offered with a priceable recipe")
+	}
+	for _, o := range cmd.Options {
+		if o.Name == "sort" {
+			t.Error("per-hour sort offered without crafting times")
+		}
+	}
+	if len(cookProfitCommand.Options) != 2 {
+		t.Error("trimming the sort option changed the shared command")
+	}
+
+	tuna, _ := catalog.ByName("cooked_tuna")
+	profits, _ := calculateCookingProfits(catalog, map[int]float64{tuna.ID: 1450, 90001: 1180}, 0.05)
+	if len(profits) != 1 || profits[0].Profit != 1450*0.95-1180 {
+		t.Errorf("profits = %+v, want cooked_tuna only", profits)
+	}
+
+	tuna.Recipe = &market.Recipe{Ingredients: tuna.Recipe.Ingredients, Seconds: 6}
+	if cmd, _ := cookProfitCommandFor(catalog.Merge([]market.Item{tuna})); cmd != cookProfitCommand {
+		t.Error("per-hour sort not offered with crafting times")
+	}
+}
@@ -1,11 +1,37 @@
 package commands
 
-import "database/sql"
+import (
+	"database/sql"
+	"log/slog"
+
+	"klutco-lil-helper/internal/logging"
+
+	"github.com/bwmarrin/discordgo"
+)
 
 // DB is the package-level database handle used by command handlers.
 var DB *sql.DB
 
+// Logger is the package-level logger used by command handlers.
+var Logger = logging.Discard()
+
 // SetDB stores the opened database connection for command handlers to use.
 func SetDB(db *sql.DB) {
 	DB = db
 }
+
+// SetLogger stores the logger for command handlers to use.
+func SetLogger(l *slog.Logger) {
+	if l != nil {
+		Logger = l
+	}
+}
+
+// interactionLogger tags the package logger with the command, guild and channel of an interaction.
+func interactionLogger(i *discordgo.InteractionCreate) *slog.Logger {
+	l := Logger.With(logging.KeyGuild, i.GuildID, logging.KeyChannel, i.ChannelID)
+	if i.Type == discordgo.InteractionApplicationCommand || i.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		l = l.With("command", i.ApplicationCommandData().Name)
+	}
+	return l
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"os"
+	"time"
+
+	"klutco-lil-helper/internal/digest"
+	"klutco-lil-helper/internal/logging"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var digestCommand = &discordgo.ApplicationCommand{
+	Name:                     "digest",
+	Description:              "Regenerate last week's clan digest",
+	DefaultMemberPermissions: &adminPermissions,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "preview",
+			Description: "Only show the digest to you instead of updating the digest channel",
+			Required:    false,
+		},
+	},
+}
+
+func digestHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "digest" {
+		return
+	}
+
+	preview := false
+	if o, ok := optionMap(i.ApplicationCommandData().Options)["preview"]; ok {
+		preview = o.BoolValue()
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		interactionLogger(i).Error("failed to acknowledge interaction", logging.Err(err))
+		return
+	}
+
+	if preview {
+		embed, err := digest.Build(DB, time.Now())
+		if err != nil {
+			interactionLogger(i).Error("failed to build digest", logging.Err(err))
+			s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+				Content: strPtr("❌ Failed to build the digest."),
+			})
+			return
+		}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Embeds: &[]*discordgo.MessageEmbed{embed},
+		})
+		return
+	}
+
+	// the digest goes to the configured channel, not the one the command was used in
+	channelName := os.Getenv("DIGEST_CHANNEL")
+	if channelName == "" {
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: strPtr("No digest channel is configured. Set `DIGEST_CHANNEL`, or use `preview:True`."),
+		})
+		return
+	}
+	channelID := guildTextChannelID(s, i.GuildID, channelName)
+	if channelID == "" {
+		interactionLogger(i).Warn("digest channel not found", "channel_name", channelName)
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: strPtr("❌ Failed to find the #" + channelName + " channel."),
+		})
+		return
+	}
+
+	if err := digest.Publish(s, DB, channelID, time.Now(), true, Logger.With(logging.KeySubsystem, "digest")); err != nil {
+		interactionLogger(i).Error("failed to regenerate digest", logging.Err(err))
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: strPtr("❌ Failed to regenerate the digest."),
+		})
+		return
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: strPtr("📰 The digest in <#" + channelID + "> has been regenerated."),
+	})
+}
+
+// guildTextChannelID returns the ID of a guild's text channel with the given name, or "".
+func guildTextChannelID(s *discordgo.Session, guildID, name string) string {
+	if s.State == nil {
+		return ""
+	}
+	guild, err := s.State.Guild(guildID)
+	if err != nil {
+		return ""
+	}
+	for _, channel := range guild.Channels {
+		if channel.Name == name && channel.Type == discordgo.ChannelTypeGuildText {
+			return channel.ID
+		}
+	}
+	return ""
+}
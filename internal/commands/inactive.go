@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"klutco-lil-helper/internal/activity"
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// defaultInactiveDays is the /inactive threshold when none is given.
+const defaultInactiveDays = 14
+
+var inactiveCommand = &discordgo.ApplicationCommand{
+	Name:        "inactive",
+	Description: "List clan members who have gone quiet",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "days",
+			Description: "Not seen for at least this many days (default 14)",
+			Required:    false,
+			MinValue:    floatPtr(1),
+			MaxValue:    365,
+		},
+	},
+}
+
+func inactiveHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "inactive" {
+		return
+	}
+
+	days := defaultInactiveDays
+	if o, ok := optionMap(i.ApplicationCommandData().Options)["days"]; ok {
+		days = int(o.IntValue())
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		interactionLogger(i).Error("failed to acknowledge interaction", logging.Err(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	members, err := activity.Load(ctx, DB, idleclans.Default, model.ClanName)
+	if err != nil {
+		interactionLogger(i).Error("failed to load member activity", logging.Err(err))
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: strPtr("❌ Failed to load the clan member list."),
+		})
+		return
+	}
+
+	now := time.Now()
+	inactive := activity.Inactive(members, now.AddDate(0, 0, -days))
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{activity.FormatEmbed(inactive, days, now)},
+	})
+}
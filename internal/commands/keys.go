@@ -2,11 +2,13 @@ package commands
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
+	"klutco-lil-helper/internal/logging"
 	"klutco-lil-helper/internal/model"
 
 	"github.com/bwmarrin/discordgo"
@@ -34,6 +36,56 @@ var keysCommand = &discordgo.ApplicationCommand{
 	},
 }
 
+// keyPoolCommand tracks the clan's key inventory. It is its own command because Discord
+// doesn't allow subcommands next to the options of /keys.
+var keyPoolCommand = &discordgo.ApplicationCommand{
+	Name:        "keypool",
+	Description: "Track the clan's boss key inventory",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "have",
+			Description: "Record how many keys of a kind you own",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "key",
+					Description:  "The name of the key.",
+					Required:     true,
+					Autocomplete: true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "count",
+					Description: "How many of this key you own.",
+					Required:    true,
+					MinValue:    floatPtr(0),
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "show",
+			Description: "Show the clan's key totals and which boss runs we can afford",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "keys_per_run",
+					Description: "Keys consumed by a single boss run (default 1).",
+					Required:    false,
+					MinValue:    floatPtr(1),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "just_for_me",
+					Description: "Only show the results to me.",
+					Required:    false,
+				},
+			},
+		},
+	},
+}
+
 func keysHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if i.Type != discordgo.InteractionApplicationCommand {
 		return
@@ -42,12 +94,39 @@ func keysHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
+	keysInfo(s, i, optionMap(i.ApplicationCommandData().Options))
+}
+
+func keyPoolHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "keypool" {
+		return
+	}
+
 	data := i.ApplicationCommandData()
-	name := data.Options[0].StringValue()
+	if len(data.Options) == 0 {
+		return
+	}
+
+	sub := data.Options[0]
+	opts := optionMap(sub.Options)
+
+	switch sub.Name {
+	case "have":
+		keysHave(s, i, opts)
+	case "show":
+		keysPool(s, i, opts)
+	}
+}
+
+func keysInfo(s *discordgo.Session, i *discordgo.InteractionCreate, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	name := opts["name"].StringValue()
 
 	justForMe := false
-	if len(data.Options) > 1 {
-		justForMe = data.Options[1].BoolValue()
+	if o, ok := opts["just_for_me"]; ok {
+		justForMe = o.BoolValue()
 	}
 
 	entry, ok := model.KeysInformation[strings.ToLower(name)]
@@ -83,15 +162,138 @@ func keysHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	})
 }
 
+func keysHave(s *discordgo.Session, i *discordgo.InteractionCreate, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	key := strings.ToLower(opts["key"].StringValue())
+	count := int(opts["count"].IntValue())
+
+	if _, ok := model.KeysInformation[key]; !ok {
+		respondEphemeral(s, i, fmt.Sprintf("Unknown key: %s", key))
+		return
+	}
+
+	gameName, ok, err := model.LinkedGameName(DB, interactionUserID(i))
+	if err != nil {
+		interactionLogger(i).Error("failed to look up linked member", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to look up your linked account.")
+		return
+	}
+	if !ok {
+		respondEphemeral(s, i, "Your Discord account is not linked to an Idle Clans member. Use `/link` first.")
+		return
+	}
+
+	if err := model.SetKeyCount(DB, gameName, key, count); err != nil {
+		interactionLogger(i).Error("failed to set key count", "key", key, "player", gameName, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to save your key count.")
+		return
+	}
+
+	respondEphemeral(s, i, fmt.Sprintf("Recorded **%d** %s key(s) for %s.", count, titleizer.String(key), gameName))
+}
+
+func keysPool(s *discordgo.Session, i *discordgo.InteractionCreate, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	keysPerRun := 1
+	if o, ok := opts["keys_per_run"]; ok && o.IntValue() > 0 {
+		keysPerRun = int(o.IntValue())
+	}
+	justForMe := false
+	if o, ok := opts["just_for_me"]; ok {
+		justForMe = o.BoolValue()
+	}
+
+	pool, err := model.GetKeyPool(DB)
+	if err != nil {
+		interactionLogger(i).Error("failed to load key pool", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load the clan key pool.")
+		return
+	}
+	vault, err := model.GetKeyCounts(DB, model.KeyVaultHolder)
+	if err != nil {
+		interactionLogger(i).Error("failed to load vault keys", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load the clan key pool.")
+		return
+	}
+
+	embed := formatKeyPoolEmbed(buildKeyPool(pool, vault, keysPerRun), keysPerRun)
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  ephemeralFlag(justForMe),
+		},
+	})
+}
+
+// keyPoolEntry is the clan-wide key situation for a single boss.
+type keyPoolEntry struct {
+	Boss  model.Boss
+	Total int
+	Vault int
+	Runs  int
+}
+
+// buildKeyPool combines pool totals with the boss list, most affordable runs first.
+func buildKeyPool(pool, vault map[string]int, keysPerRun int) []keyPoolEntry {
+	if keysPerRun <= 0 {
+		keysPerRun = 1
+	}
+
+	entries := make([]keyPoolEntry, 0, len(model.AllBosses))
+	for _, boss := range model.AllBosses {
+		total := pool[strings.ToLower(boss.Key)]
+		entries = append(entries, keyPoolEntry{
+			Boss:  boss,
+			Total: total,
+			Vault: vault[strings.ToLower(boss.Key)],
+			Runs:  total / keysPerRun,
+		})
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].Runs > entries[b].Runs
+	})
+	return entries
+}
+
+// formatKeyPoolEmbed renders the key pool with a suggested boss for today.
+func formatKeyPoolEmbed(entries []keyPoolEntry, keysPerRun int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:  "Clan Key Pool",
+		Color:  0xFFD700,
+		Fields: []*discordgo.MessageEmbedField{},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Member counts via /keypool have, vault counts from clan logs · %d key(s) per run", keysPerRun),
+		},
+	}
+
+	if len(entries) == 0 || entries[0].Runs == 0 {
+		embed.Description = "The clan cannot afford any boss run right now."
+	} else {
+		best := entries[0]
+		embed.Description = fmt.Sprintf("Suggested run today: **%s** (%d run(s) available)", titleizer.String(best.Boss.Name), best.Runs)
+	}
+
+	for _, e := range entries {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("%s - %s", titleizer.String(e.Boss.Name), titleizer.String(e.Boss.Key)),
+			Value:  fmt.Sprintf("%d keys (%d in vault), %d run(s)", e.Total, e.Vault, e.Runs),
+			Inline: true,
+		})
+	}
+
+	return embed
+}
+
 func keysAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
 		return
 	}
-	if i.ApplicationCommandData().Name != "keys" {
+	if name := i.ApplicationCommandData().Name; name != "keys" && name != "keypool" {
 		return
 	}
 
-	current := i.ApplicationCommandData().Options[0].StringValue()
+	current := focusedValue(i.ApplicationCommandData().Options)
 	var choices []*discordgo.ApplicationCommandOptionChoice
 
 	for key := range model.KeysInformation {
@@ -0,0 +1,302 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var linkCommand = &discordgo.ApplicationCommand{
+	Name:        "link",
+	Description: "Ask to link your Discord account to your Idle Clans character",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "name",
+			Description: "Your in-game name",
+			Required:    true,
+		},
+	},
+}
+
+var linkUserOptions = []*discordgo.ApplicationCommandOption{
+	{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        "name",
+		Description: "The in-game name",
+		Required:    true,
+	},
+	{
+		Type:        discordgo.ApplicationCommandOptionUser,
+		Name:        "user",
+		Description: "The Discord account",
+		Required:    true,
+	},
+}
+
+var linksCommand = &discordgo.ApplicationCommand{
+	Name:                     "links",
+	Description:              "Review and manage links between Discord accounts and game names",
+	DefaultMemberPermissions: &adminPermissions,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "pending",
+			Description: "List link requests waiting for approval",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "approve",
+			Description: "Approve a member's link request",
+			Options:     linkUserOptions,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "reject",
+			Description: "Reject a member's link request",
+			Options:     linkUserOptions,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "set",
+			Description: "Link a game name to an account, replacing any existing link",
+			Options:     linkUserOptions,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "unlink",
+			Description: "Remove the link of a game name",
+			Options:     linkUserOptions[:1],
+		},
+	},
+}
+
+func linkHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "link" {
+		return
+	}
+
+	opts := optionMap(i.ApplicationCommandData().Options)
+	gameName := strings.TrimSpace(opts["name"].StringValue())
+	userID := interactionUserID(i)
+	if gameName == "" || userID == "" {
+		respondEphemeral(s, i, "❌ Please give your in-game name.")
+		return
+	}
+
+	logger := interactionLogger(i).With("player", gameName)
+	if current, ok, err := model.LinkedDiscordID(DB, gameName); err != nil {
+		logger.Error("failed to look up link", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to request the link.")
+		return
+	} else if ok && current == userID {
+		respondEphemeral(s, i, fmt.Sprintf("🔗 Your Discord account is already linked to **%s**.", gameName))
+		return
+	}
+
+	if err := model.RequestLink(DB, gameName, userID); err != nil {
+		if errors.Is(err, model.ErrAlreadyLinked) {
+			respondEphemeral(s, i, fmt.Sprintf("❌ **%s** is already linked to another Discord account. Ask an officer to fix it with `/links`.", gameName))
+			return
+		}
+		logger.Error("failed to request link", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to request the link.")
+		return
+	}
+
+	logger.Info("link requested")
+	respondEphemeral(s, i, fmt.Sprintf("📝 Asked to link your Discord account to **%s**. An officer will confirm it's you; until then you get no clan roles.", gameName))
+}
+
+func linksHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "links" {
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+
+	sub := data.Options[0]
+	if sub.Name == "pending" {
+		requests, err := model.ListLinkRequests(DB)
+		if err != nil {
+			interactionLogger(i).Error("failed to list link requests", logging.Err(err))
+			respondEphemeral(s, i, "❌ Failed to load link requests.")
+			return
+		}
+		respondEphemeral(s, i, formatLinkRequests(requests))
+		return
+	}
+
+	opts := optionMap(sub.Options)
+	gameName := strings.TrimSpace(opts["name"].StringValue())
+	userID := ""
+	if o, ok := opts["user"]; ok {
+		userID = o.UserValue(nil).ID
+	}
+	logger := interactionLogger(i).With("player", gameName, "user", userID)
+
+	switch sub.Name {
+	case "approve":
+		found, err := model.ApproveLinkRequest(DB, gameName, userID)
+		switch {
+		case errors.Is(err, model.ErrAlreadyLinked):
+			respondEphemeral(s, i, fmt.Sprintf("❌ **%s** was linked to another account meanwhile. Use `/links set` to override it.", gameName))
+			return
+		case err != nil:
+			logger.Error("failed to approve link request", logging.Err(err))
+			respondEphemeral(s, i, "❌ Failed to approve the link.")
+			return
+		case !found:
+			respondEphemeral(s, i, fmt.Sprintf("<@%s> has no pending request for **%s**. See `/links pending`.", userID, gameName))
+			return
+		}
+		logger.Info("link approved")
+		respondEphemeral(s, i, linkedMessage(s, i.GuildID, gameName, userID, logger))
+
+	case "reject":
+		found, err := model.RejectLinkRequest(DB, gameName, userID)
+		if err != nil {
+			logger.Error("failed to reject link request", logging.Err(err))
+			respondEphemeral(s, i, "❌ Failed to reject the link.")
+			return
+		}
+		if !found {
+			respondEphemeral(s, i, fmt.Sprintf("<@%s> has no pending request for **%s**.", userID, gameName))
+			return
+		}
+		logger.Info("link rejected")
+		respondEphemeral(s, i, fmt.Sprintf("🗑️ Rejected <@%s>'s request for **%s**.", userID, gameName))
+
+	case "set":
+		previous, linked, err := model.LinkedDiscordID(DB, gameName)
+		if err != nil {
+			logger.Error("failed to look up link", logging.Err(err))
+			respondEphemeral(s, i, "❌ Failed to link the account.")
+			return
+		}
+		if err := model.ForceLinkMember(DB, gameName, userID); err != nil {
+			logger.Error("failed to link member", logging.Err(err))
+			respondEphemeral(s, i, "❌ Failed to link the account.")
+			return
+		}
+		if linked && previous != userID {
+			revokeClanRoles(s, i.GuildID, previous, logger)
+		}
+		logger.Info("link set by officer", "previous", previous)
+		respondEphemeral(s, i, linkedMessage(s, i.GuildID, gameName, userID, logger))
+
+	case "unlink":
+		previous, found, err := model.UnlinkMember(DB, gameName)
+		if err != nil {
+			logger.Error("failed to unlink member", logging.Err(err))
+			respondEphemeral(s, i, "❌ Failed to unlink the name.")
+			return
+		}
+		if !found {
+			respondEphemeral(s, i, fmt.Sprintf("**%s** isn't linked.", gameName))
+			return
+		}
+		revokeClanRoles(s, i.GuildID, previous, logger)
+		logger.Info("link removed by officer", "previous", previous)
+		respondEphemeral(s, i, fmt.Sprintf("✂️ Unlinked **%s** from <@%s> and removed their clan roles.", gameName, previous))
+	}
+}
+
+// linkedMessage confirms a link to an officer, giving the member the clan role when they are in the clan.
+func linkedMessage(s *discordgo.Session, guildID, gameName, userID string, logger *slog.Logger) string {
+	content := fmt.Sprintf("🔗 Linked <@%s> to **%s**.", userID, gameName)
+	if assignClanRole(s, guildID, gameName, userID, logger) {
+		content += " They now have the clan role."
+	}
+	return content
+}
+
+// formatLinkRequests lists pending link requests for officers.
+func formatLinkRequests(requests []model.LinkRequest) string {
+	if len(requests) == 0 {
+		return "No pending link requests."
+	}
+	var sb strings.Builder
+	sb.WriteString("**Pending link requests**")
+	for _, r := range requests {
+		fmt.Fprintf(&sb, "\n• **%s** ← <@%s>", r.GameName, r.DiscordID)
+		if !r.RequestedAt.IsZero() {
+			fmt.Fprintf(&sb, " (<t:%d:R>)", r.RequestedAt.Unix())
+		}
+	}
+	sb.WriteString("\nApprove with `/links approve`, or `/links reject` claims you can't confirm.")
+	return sb.String()
+}
+
+// assignClanRole gives a newly linked member the guild's clan role when they are in the clan.
+// It reports whether the role was assigned.
+func assignClanRole(s *discordgo.Session, guildID, gameName, userID string, logger *slog.Logger) bool {
+	if guildID == "" {
+		return false
+	}
+	settings, ok, err := model.GetWelcomeSettings(DB, guildID)
+	if err != nil {
+		logger.Error("failed to load welcome settings", logging.Err(err))
+		return false
+	}
+	if !ok || settings.RoleID == "" {
+		return false
+	}
+
+	member, _, err := model.GetMember(DB, gameName)
+	if err != nil {
+		logger.Error("failed to load member", logging.Err(err))
+		return false
+	}
+	if !member.InClan() {
+		return false
+	}
+
+	if err := s.GuildMemberRoleAdd(guildID, userID, settings.RoleID); err != nil {
+		logger.Error("failed to assign clan role", "role", settings.RoleID, logging.Err(err))
+		return false
+	}
+	return true
+}
+
+// revokeClanRoles removes the clan role and every mapped rank role from an account that
+// lost its link, since neither the rank sync nor the welcome workflow look at it anymore.
+func revokeClanRoles(s *discordgo.Session, guildID, userID string, logger *slog.Logger) {
+	if guildID == "" || userID == "" {
+		return
+	}
+	var roleIDs []string
+	if settings, ok, err := model.GetWelcomeSettings(DB, guildID); err != nil {
+		logger.Error("failed to load welcome settings", logging.Err(err))
+	} else if ok && settings.RoleID != "" {
+		roleIDs = append(roleIDs, settings.RoleID)
+	}
+	rankRoles, err := model.ListRankRoles(DB, guildID)
+	if err != nil {
+		logger.Error("failed to load rank roles", logging.Err(err))
+	}
+	for _, roleID := range rankRoles {
+		roleIDs = append(roleIDs, roleID)
+	}
+
+	for _, roleID := range roleIDs {
+		if err := s.GuildMemberRoleRemove(guildID, userID, roleID); err != nil {
+			logger.Warn("failed to remove role from unlinked account", "role", roleID, "user", userID, logging.Err(err))
+		}
+	}
+}
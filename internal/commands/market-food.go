@@ -2,83 +2,17 @@ package commands
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
 	"sort"
 	"strings"
 	"time"
 
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+
 	"github.com/bwmarrin/discordgo"
 )
 
-// Food items (name_id) and their healing values
-var foodHealingValues = map[string]int{
-	"cooked_piranha":       2,
-	"cooked_perch":         3,
-	"cooked_mackerel":      4,
-	"cooked_cod":           6,
-	"cooked_trout":         7,
-	"cooked_salmon":        8,
-	"cooked_carp":          10,
-	"cooked_zander":        12,
-	"cooked_pufferfish":    14,
-	"cooked_anglerfish":    16,
-	"cooked_tuna":          17,
-	"cooked_bloodmoon_eel": 24,
-	"cooked_meat":          4,
-	"cooked_giant_meat":    8,
-	"cooked_quality_meat":  12,
-	"cooked_superior_meat": 18,
-	"cooked_apex_meat":     20,
-	"potato_soup":          5,
-	"meat_burger":          7,
-	"cod_soup":             10,
-	"blueberry_pie":        11,
-	"salmon_salad":         14,
-	"porcini_soup":         17,
-	"stew":                 19,
-	"power_pizza":          22,
-}
-
-// Item ID mapping (name_id -> internal_id)
-// This is static data that never changes
-var itemIDMapping = map[string]int{
-	"cooked_mackerel":      100,
-	"cooked_perch":         102,
-	"cooked_trout":         104,
-	"cooked_salmon":        105,
-	"cooked_carp":          106,
-	"cooked_meat":          114,
-	"cooked_giant_meat":    115,
-	"cooked_quality_meat":  116,
-	"cooked_superior_meat": 117,
-	"potato_soup":          140,
-	"meat_burger":          141,
-	"cod_soup":             143,
-	"blueberry_pie":        144,
-	"salmon_salad":         145,
-	"porcini_soup":         146,
-	"power_pizza":          148,
-	"cooked_anglerfish":    156,
-	"cooked_zander":        158,
-	"cooked_piranha":       160,
-	"cooked_pufferfish":    162,
-	"cooked_cod":           164,
-	"stew":                 559,
-	"cooked_tuna":          562,
-	"cooked_bloodmoon_eel": 888,
-	"cooked_apex_meat":     906,
-}
-
-// MarketPriceItem represents market price data
-type MarketPriceItem struct {
-	ItemID          int     `json:"itemId"`
-	LowestSellPrice float64 `json:"lowestSellPrice"`
-}
-
 // FoodValueResult represents calculated results for display
 type FoodValueResult struct {
 	Name           string
@@ -97,9 +31,41 @@ var marketFoodCommand = &discordgo.ApplicationCommand{
 			Description: "Only show the results to me.",
 			Required:    false,
 		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "hp_needed",
+			Description: "Plan the cheapest purchase giving at least this much total HP.",
+			Required:    false,
+			MinValue:    floatPtr(1),
+			MaxValue:    maxPlannedHP,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "max_price",
+			Description: "Ignore foods costing more than this many gold per item.",
+			Required:    false,
+			MinValue:    floatPtr(1),
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "min_heal",
+			Description: "Ignore foods healing less than this per bite.",
+			Required:    false,
+			MinValue:    floatPtr(1),
+		},
 	},
 }
 
+// maxPlannedHP bounds the healing planner's search space.
+const maxPlannedHP = 1000000
+
+// HealingPlan is the cheapest combination of foods reaching a healing target.
+type HealingPlan struct {
+	Quantities map[string]int // food name -> items to buy
+	TotalHP    int
+	TotalCost  float64
+}
+
 func marketFoodHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	if i.Type != discordgo.InteractionApplicationCommand {
 		return
@@ -108,11 +74,21 @@ func marketFoodHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
-	data := i.ApplicationCommandData()
+	opts := optionMap(i.ApplicationCommandData().Options)
 
 	justForMe := false
-	if len(data.Options) > 0 {
-		justForMe = data.Options[0].BoolValue()
+	if o, ok := opts["just_for_me"]; ok {
+		justForMe = o.BoolValue()
+	}
+	var hpNeeded, maxPrice, minHeal int
+	if o, ok := opts["hp_needed"]; ok {
+		hpNeeded = int(o.IntValue())
+	}
+	if o, ok := opts["max_price"]; ok {
+		maxPrice = int(o.IntValue())
+	}
+	if o, ok := opts["min_heal"]; ok {
+		minHeal = int(o.IntValue())
 	}
 
 	// Create context with timeout
@@ -120,9 +96,9 @@ func marketFoodHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	defer cancel()
 
 	// Fetch market prices
-	priceMap, err := fetchMarketPrices(ctx)
+	snap, err := market.Prices.Get(ctx)
 	if err != nil {
-		log.Printf("[market-food] failed to fetch market prices: %v", err)
+		interactionLogger(i).Error("failed to fetch market prices", logging.Err(err))
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
@@ -134,7 +110,7 @@ func marketFoodHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 
 	// Calculate food values
-	results := calculateFoodValues(priceMap)
+	results := filterFoods(calculateFoodValues(snap.LowestSellPrices()), maxPrice, minHeal)
 
 	if len(results) == 0 {
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -147,101 +123,53 @@ func marketFoodHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		return
 	}
 
+	// The plan considers every food: a dominated item can still fill the last few HP cheaply
+	var plan HealingPlan
+	if hpNeeded > 0 {
+		plan = planHealingPurchase(results, hpNeeded)
+	}
+
 	// Filter dominated items
 	totalCount := len(results)
 	results = filterDominatedItems(results)
-	log.Printf("[market-food] showing %d non-dominated items (filtered from %d)", len(results), totalCount)
+	interactionLogger(i).Debug("showing non-dominated items", "shown", len(results), "total", totalCount)
 
 	// Create and send embed
-	embed := formatFoodEmbed(results)
+	foodEmbed := formatFoodEmbed(results)
+	foodEmbed.Footer.Text = marketDataFooter(snap)
+	embeds := []*discordgo.MessageEmbed{foodEmbed}
+	if hpNeeded > 0 {
+		embeds = append(embeds, formatHealingPlanEmbed(plan, hpNeeded))
+	}
 
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{embed},
+			Embeds: embeds,
 			Flags:  ephemeralFlag(justForMe),
 		},
 	})
 }
 
-// fetchMarketPrices fetches latest market prices from the API
-func fetchMarketPrices(ctx context.Context) (map[int]float64, error) {
-	url := "https://query.idleclans.com/api/PlayerMarket/items/prices/latest?includeAveragePrice=true"
-	var marketData []MarketPriceItem
-	var lastErr error
-
-	// Retry logic: 3 attempts with exponential backoff
-	for attempt := 0; attempt < 3; attempt++ {
-		if attempt > 0 {
-			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
-			select {
-			case <-time.After(backoff):
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			}
-		}
-
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		client := &http.Client{Timeout: 15 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("API returned status %d", resp.StatusCode)
-			continue
-		}
-
-		err = json.Unmarshal(body, &marketData)
-		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		// Success - build price map
-		priceMap := make(map[int]float64)
-		for _, item := range marketData {
-			priceMap[item.ItemID] = item.LowestSellPrice
-		}
-		return priceMap, nil
-	}
-
-	return nil, fmt.Errorf("failed after 3 attempts: %w", lastErr)
-}
-
 // calculateFoodValues combines data and calculates cost per HP
 func calculateFoodValues(priceMap map[int]float64) []FoodValueResult {
 	var results []FoodValueResult
 
-	for foodName, healing := range foodHealingValues {
-		itemID, ok := itemIDMapping[foodName]
-		if !ok {
-			log.Printf("[market-food] no item ID found for: %s", foodName)
+	for _, food := range market.DefaultCatalog().InCategory(market.CategoryFood) {
+		foodName, healing := food.Name, food.Healing
+		if healing <= 0 {
+			Logger.Debug("no healing value", "food", foodName)
 			continue
 		}
 
-		price, ok := priceMap[itemID]
+		price, ok := priceMap[food.ID]
 		if !ok {
-			log.Printf("[market-food] no price data for: %s", foodName)
+			Logger.Debug("no price data", "food", foodName)
 			continue
 		}
 
 		if price <= 0 {
-			log.Printf("[market-food] invalid price for %s: %.2f", foodName, price)
+			Logger.Debug("invalid price", "food", foodName, "price", price)
 			continue
 		}
 
@@ -263,6 +191,92 @@ func calculateFoodValues(priceMap map[int]float64) []FoodValueResult {
 	return results
 }
 
+// filterFoods drops foods above maxPrice per item or healing less than minHeal.
+// A zero limit disables that filter.
+func filterFoods(results []FoodValueResult, maxPrice, minHeal int) []FoodValueResult {
+	var filtered []FoodValueResult
+	for _, r := range results {
+		if maxPrice > 0 && r.Price > float64(maxPrice) {
+			continue
+		}
+		if r.Healing < minHeal {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+// planHealingPurchase finds the cheapest quantities of foods totalling at least hpNeeded HP.
+// It is an unbounded knapsack over HP: cost[h] is the cheapest way to get h or more HP.
+// Returns an empty plan when no foods are available.
+func planHealingPurchase(foods []FoodValueResult, hpNeeded int) HealingPlan {
+	plan := HealingPlan{Quantities: make(map[string]int)}
+	if hpNeeded <= 0 || len(foods) == 0 {
+		return plan
+	}
+	if hpNeeded > maxPlannedHP {
+		hpNeeded = maxPlannedHP
+	}
+
+	cost := make([]float64, hpNeeded+1)
+	choice := make([]int, hpNeeded+1)
+	for h := 1; h <= hpNeeded; h++ {
+		cost[h] = -1
+		for f, food := range foods {
+			prev := h - food.Healing
+			if prev < 0 {
+				prev = 0
+			}
+			if c := cost[prev] + food.Price; cost[h] < 0 || c < cost[h] {
+				cost[h] = c
+				choice[h] = f
+			}
+		}
+	}
+
+	for h := hpNeeded; h > 0; {
+		food := foods[choice[h]]
+		plan.Quantities[food.Name]++
+		plan.TotalHP += food.Healing
+		h -= food.Healing
+	}
+	plan.TotalCost = cost[hpNeeded]
+	return plan
+}
+
+// formatHealingPlanEmbed creates the Discord embed for a healing purchase plan
+func formatHealingPlanEmbed(plan HealingPlan, hpNeeded int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:  fmt.Sprintf("Cheapest Way to Get %d HP", hpNeeded),
+		Color:  0x00FF00, // Green
+		Fields: []*discordgo.MessageEmbedField{},
+	}
+
+	if len(plan.Quantities) == 0 {
+		embed.Description = "No food matches your filters."
+		return embed
+	}
+
+	embed.Description = fmt.Sprintf("Buy the following for **%.0f g** total (%d HP)", plan.TotalCost, plan.TotalHP)
+
+	names := make([]string, 0, len(plan.Quantities))
+	for name := range plan.Quantities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   titleizer.String(strings.ReplaceAll(name, "_", " ")),
+			Value:  fmt.Sprintf("x%d", plan.Quantities[name]),
+			Inline: true,
+		})
+	}
+
+	return embed
+}
+
 // filterDominatedItems removes items that are economically dominated
 // An item is dominated if another item exists that heals >= HP and costs < per HP
 func filterDominatedItems(results []FoodValueResult) []FoodValueResult {
@@ -0,0 +1,62 @@
+package commands
+
+import "testing"
+
+func TestPlanHealingPurchase(t *testing.T) {
+	foods := []FoodValueResult{
+		{Name: "small", Healing: 3, Price: 4},   // 1.33 g/HP
+		{Name: "big", Healing: 10, Price: 10},   // 1.00 g/HP
+		{Name: "pricey", Healing: 7, Price: 50}, // never worth it
+	}
+
+	tests := []struct {
+		name     string
+		hpNeeded int
+		wantCost float64
+		wantQty  map[string]int
+	}{
+		{"exact multiple of best food", 30, 30, map[string]int{"big": 3}},
+		{"top up with small food", 23, 24, map[string]int{"big": 2, "small": 1}},
+		{"overshoot when cheaper", 9, 10, map[string]int{"big": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planHealingPurchase(foods, tt.hpNeeded)
+			if plan.TotalCost != tt.wantCost {
+				t.Errorf("TotalCost = %v, want %v", plan.TotalCost, tt.wantCost)
+			}
+			if plan.TotalHP < tt.hpNeeded {
+				t.Errorf("TotalHP = %d, want at least %d", plan.TotalHP, tt.hpNeeded)
+			}
+			if len(plan.Quantities) != len(tt.wantQty) {
+				t.Fatalf("Quantities = %v, want %v", plan.Quantities, tt.wantQty)
+			}
+			for name, qty := range tt.wantQty {
+				if plan.Quantities[name] != qty {
+					t.Errorf("Quantities[%s] = %d, want %d", name, plan.Quantities[name], qty)
+				}
+			}
+		})
+	}
+
+	if plan := planHealingPurchase(nil, 100); len(plan.Quantities) != 0 {
+		t.Errorf("plan with no foods = %+v, want empty", plan)
+	}
+}
+
+func TestFilterFoods(t *testing.T) {
+	foods := []FoodValueResult{
+		{Name: "a", Healing: 5, Price: 100},
+		{Name: "b", Healing: 20, Price: 500},
+		{Name: "c", Healing: 12, Price: 250},
+	}
+
+	got := filterFoods(foods, 300, 10)
+	if len(got) != 1 || got[0].Name != "c" {
+		t.Errorf("filterFoods(300, 10) = %+v, want only c", got)
+	}
+	if got := filterFoods(foods, 0, 0); len(got) != 3 {
+		t.Errorf("filterFoods without limits kept %d items, want 3", len(got))
+	}
+}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"klutco-lil-helper/internal/market"
+)
+
+// marketDataFooter describes where market data came from and how old it is.
+// Stale data served while the API is down is called out explicitly.
+func marketDataFooter(snap market.Snapshot) string {
+	age := formatAge(snap.Age())
+	if snap.Stale {
+		return "⚠️ Market API unavailable · showing data from " + age
+	}
+	return "Data from Idle Clans market API · updated " + age
+}
+
+// formatAge renders a duration as "just now", "5m ago" or "2h10m ago".
+func formatAge(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%02dm ago", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
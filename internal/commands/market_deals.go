@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// defaultMinDiscount is the discount below average, in percent, used when none is given.
+const defaultMinDiscount = 20
+
+var marketDealsCommand = &discordgo.ApplicationCommand{
+	Name:        "market-deals",
+	Description: "List items currently listed well below their average price",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "category",
+			Description:  "Only show items of this category.",
+			Required:     false,
+			Autocomplete: true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionInteger,
+			Name:        "min_discount",
+			Description: "Minimum discount below the average price, in percent (default 20).",
+			Required:    false,
+			MinValue:    floatPtr(1),
+			MaxValue:    99,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "just_for_me",
+			Description: "Only show the results to me.",
+			Required:    false,
+		},
+	},
+}
+
+func marketDealsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "market-deals" {
+		return
+	}
+
+	opts := optionMap(i.ApplicationCommandData().Options)
+
+	justForMe := false
+	if o, ok := opts["just_for_me"]; ok {
+		justForMe = o.BoolValue()
+	}
+	minDiscount := defaultMinDiscount
+	if o, ok := opts["min_discount"]; ok {
+		minDiscount = int(o.IntValue())
+	}
+	category := ""
+	if o, ok := opts["category"]; ok {
+		category = strings.ToLower(strings.TrimSpace(o.StringValue()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	snap, err := market.Prices.Get(ctx)
+	if err != nil {
+		interactionLogger(i).Error("failed to fetch market prices", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to fetch market data. The API may be temporarily unavailable. Please try again later.")
+		return
+	}
+
+	catalog := market.DefaultCatalog()
+	deals := filterDealsByCategory(catalog, market.FindDeals(snap.Items, float64(minDiscount)/100), category)
+
+	embed := formatDealsEmbed(catalog, deals, minDiscount, category)
+	embed.Footer.Text = marketDataFooter(snap)
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  ephemeralFlag(justForMe),
+		},
+	})
+}
+
+// filterDealsByCategory keeps deals on catalog items of the given category.
+// An empty category keeps everything, including items missing from the catalog.
+func filterDealsByCategory(catalog *market.Catalog, deals []market.Deal, category string) []market.Deal {
+	if category == "" {
+		return deals
+	}
+	var filtered []market.Deal
+	for _, d := range deals {
+		if it, ok := catalog.ByID(d.ItemID); ok && strings.EqualFold(it.Category, category) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// formatDealsEmbed creates the Discord embed for market deals
+func formatDealsEmbed(catalog *market.Catalog, deals []market.Deal, minDiscount int, category string) *discordgo.MessageEmbed {
+	scope := "items"
+	if category != "" {
+		scope = category + " items"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Market Deals",
+		Description: fmt.Sprintf("%d %s listed at least %d%% below their average price", len(deals), scope, minDiscount),
+		Color:       0x00FF00, // Green
+		Fields:      []*discordgo.MessageEmbedField{},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Data from Idle Clans market API",
+		},
+	}
+
+	for idx, d := range deals {
+		if idx >= 25 {
+			break
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   catalog.DisplayName(d.ItemID),
+			Value:  fmt.Sprintf("%.0f g (avg %.0f g, -%.0f%%)", d.LowestSellPrice, d.AveragePrice, d.Discount*100),
+			Inline: true,
+		})
+	}
+
+	return embed
+}
+
+func marketDealsAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+		return
+	}
+	if i.ApplicationCommandData().Name != "market-deals" {
+		return
+	}
+
+	current := strings.ToLower(focusedValue(i.ApplicationCommandData().Options))
+	var choices []*discordgo.ApplicationCommandOptionChoice
+
+	for _, cat := range market.DefaultCatalog().Categories() {
+		if strings.Contains(cat, current) {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  titleizer.String(cat),
+				Value: cat,
+			})
+		}
+		if len(choices) >= 25 {
+			break
+		}
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+}
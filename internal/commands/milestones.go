@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var milestonesCommand = &discordgo.ApplicationCommand{
+	Name:        "milestones",
+	Description: "Turn announcements of your level milestones on or off",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "announce",
+			Description: "Whether your level milestones are celebrated in #general",
+			Required:    true,
+		},
+	},
+}
+
+func milestonesHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "milestones" {
+		return
+	}
+
+	opts := optionMap(i.ApplicationCommandData().Options)
+	announce := opts["announce"].BoolValue()
+	userID := interactionUserID(i)
+	if userID == "" {
+		return
+	}
+
+	if err := model.SetMilestoneOptOut(DB, userID, !announce); err != nil {
+		interactionLogger(i).Error("failed to save milestone opt-out", "announce", announce, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to save your milestone setting.")
+		return
+	}
+
+	if announce {
+		respondEphemeral(s, i, "🎉 Your level milestones will be announced again.")
+		return
+	}
+	respondEphemeral(s, i, "🔕 Your level milestones won't be announced anymore. Use `/milestones announce:True` to turn them back on.")
+}
@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var playerCommand = &discordgo.ApplicationCommand{
+	Name:        "player",
+	Description: "Show a player's Idle Clans profile",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "name",
+			Description:  "The in-game name. Defaults to your linked account.",
+			Required:     false,
+			Autocomplete: true,
+		},
+	},
+}
+
+func playerHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "player" {
+		return
+	}
+
+	var name string
+	if o, ok := optionMap(i.ApplicationCommandData().Options)["name"]; ok {
+		name = strings.TrimSpace(o.StringValue())
+	}
+	if name == "" {
+		linked, ok, err := model.LinkedGameName(DB, interactionUserID(i))
+		if err != nil {
+			interactionLogger(i).Error("failed to look up linked member", logging.Err(err))
+			respondEphemeral(s, i, "❌ Failed to look up your linked account.")
+			return
+		}
+		if !ok {
+			respondEphemeral(s, i, "Give a player name, or link your account with `/link` first.")
+			return
+		}
+		name = linked
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		interactionLogger(i).Error("failed to acknowledge interaction", logging.Err(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	player, err := idleclans.Default.PlayerProfile(ctx, name)
+	if err != nil {
+		content := "❌ Failed to load the player profile."
+		if errors.Is(err, idleclans.ErrNotFound) {
+			content = fmt.Sprintf("No player named **%s**.", name)
+		} else {
+			interactionLogger(i).Error("failed to fetch player profile", "player", name, logging.Err(err))
+		}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content: strPtr(content),
+		})
+		return
+	}
+
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{formatPlayerEmbed(player)},
+	})
+}
+
+func playerAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+		return
+	}
+	if i.ApplicationCommandData().Name != "player" {
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: memberChoices(rosterNames(), focusedValue(i.ApplicationCommandData().Options)),
+		},
+	})
+}
+
+// formatPlayerEmbed renders a player's profile with a two-column table of skill levels.
+func formatPlayerEmbed(p *idleclans.Player) *discordgo.MessageEmbed {
+	clan := p.ClanName
+	if clan == "" {
+		clan = "—"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: p.Username,
+		Color: 0x5865F2, // Blurple
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Clan", Value: clan, Inline: true},
+			{Name: "Game mode", Value: p.GameModeName(), Inline: true},
+			{Name: "Total level", Value: fmt.Sprintf("%d (%s XP)", p.TotalLevel(), formatXP(p.TotalXP())), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Data from Idle Clans player API",
+		},
+	}
+
+	skills := p.Skills()
+	if len(skills) == 0 {
+		return embed
+	}
+
+	var sb strings.Builder
+	sb.WriteString("```\n")
+	half := (len(skills) + 1) / 2
+	for row := 0; row < half; row++ {
+		left := skills[row]
+		fmt.Fprintf(&sb, "%-12s %3d", left.DisplayName(), left.Level)
+		if right := row + half; right < len(skills) {
+			fmt.Fprintf(&sb, "   %-12s %3d", skills[right].DisplayName(), skills[right].Level)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("```")
+	embed.Description = sb.String()
+	return embed
+}
+
+// formatXP shortens an experience amount, e.g. 13034431 -> "13.0M".
+func formatXP(xp float64) string {
+	switch {
+	case xp >= 1e9:
+		return fmt.Sprintf("%.1fB", xp/1e9)
+	case xp >= 1e6:
+		return fmt.Sprintf("%.1fM", xp/1e6)
+	case xp >= 1e3:
+		return fmt.Sprintf("%.1fK", xp/1e3)
+	}
+	return fmt.Sprintf("%.0f", xp)
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"klutco-lil-helper/internal/idleclans"
+)
+
+func TestFormatPlayerEmbed(t *testing.T) {
+	p := &idleclans.Player{
+		Username: "ImaKlutz",
+		GameMode: "default",
+		ClanName: "KlutzCo",
+		SkillExperiences: map[string]float64{
+			"attack":      13034431,
+			"strength":    1154,
+			"woodcutting": 104273167,
+		},
+	}
+
+	embed := formatPlayerEmbed(p)
+
+	if embed.Title != "ImaKlutz" {
+		t.Errorf("title = %q", embed.Title)
+	}
+	if got := embed.Fields[1].Value; got != "Standard" {
+		t.Errorf("game mode = %q, want Standard", got)
+	}
+	if got := embed.Fields[2].Value; got != "229 (117.3M XP)" {
+		t.Errorf("total level = %q", got)
+	}
+	// two columns: attack and strength on the left, woodcutting on the right
+	lines := strings.Split(strings.Trim(embed.Description, "`\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "Attack") || !strings.Contains(lines[0], "Woodcutting  120") {
+		t.Errorf("skill table = %q", embed.Description)
+	}
+}
+
+func TestMemberChoices(t *testing.T) {
+	names := []string{"guildan", "ImaKlutz", "moraxam"}
+
+	if got := memberChoices(names, ""); len(got) != 3 {
+		t.Errorf("empty query gave %d choices, want 3", len(got))
+	}
+	got := memberChoices(names, "KLU")
+	if len(got) != 1 || got[0].Value != "ImaKlutz" {
+		t.Errorf("choices for KLU = %+v", got)
+	}
+}
+
+func TestFormatXP(t *testing.T) {
+	tests := map[float64]string{
+		950:        "950",
+		1154:       "1.2K",
+		13034431:   "13.0M",
+		2500000000: "2.5B",
+	}
+	for xp, want := range tests {
+		if got := formatXP(xp); got != want {
+			t.Errorf("formatXP(%v) = %q, want %q", xp, got, want)
+		}
+	}
+}
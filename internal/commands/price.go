@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var priceCommand = &discordgo.ApplicationCommand{
+	Name:        "price",
+	Description: "Show the current market price of any item",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "item",
+			Description:  "The item name (or its numeric ID).",
+			Required:     true,
+			Autocomplete: true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "just_for_me",
+			Description: "Only show the price to me.",
+			Required:    false,
+		},
+	},
+}
+
+func priceHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "price" {
+		return
+	}
+
+	opts := optionMap(i.ApplicationCommandData().Options)
+	query := opts["item"].StringValue()
+
+	justForMe := false
+	if o, ok := opts["just_for_me"]; ok {
+		justForMe = o.BoolValue()
+	}
+
+	item, ok := resolveItem(query)
+	if !ok {
+		respondEphemeral(s, i, fmt.Sprintf("Unknown item: %s", query))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	snap, err := market.Prices.Get(ctx)
+	if err != nil {
+		interactionLogger(i).Error("failed to fetch market prices", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to fetch market data. The API may be temporarily unavailable. Please try again later.")
+		return
+	}
+
+	var price *market.PriceItem
+	for idx := range snap.Items {
+		if snap.Items[idx].ItemID == item.ID {
+			price = &snap.Items[idx]
+			break
+		}
+	}
+	if price == nil {
+		respondEphemeral(s, i, fmt.Sprintf("⚠️ %s is not currently listed on the market.", item.DisplayName()))
+		return
+	}
+
+	embed := formatPriceEmbed(item, *price, marketDataFooter(snap))
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  ephemeralFlag(justForMe),
+		},
+	})
+}
+
+// resolveItem finds a catalog item by name or by its numeric item ID, e.g. "#562".
+func resolveItem(query string) (market.Item, bool) {
+	catalog := market.DefaultCatalog()
+	if it, ok := catalog.ByName(query); ok {
+		return it, true
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(query), "#"))
+	if err != nil {
+		return market.Item{}, false
+	}
+	return catalog.ByID(id)
+}
+
+// formatPriceEmbed renders the market price of a single item, footer describing the data source.
+func formatPriceEmbed(item market.Item, price market.PriceItem, footer string) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:  item.DisplayName(),
+		Color:  0x00FF00, // Green
+		Fields: []*discordgo.MessageEmbedField{},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Item #%d · %s", item.ID, footer),
+		},
+	}
+
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Lowest sell",
+		Value:  fmt.Sprintf("%.0f g", price.LowestSellPrice),
+		Inline: true,
+	})
+
+	if price.AveragePrice > 0 {
+		diff := (price.LowestSellPrice - price.AveragePrice) / price.AveragePrice * 100
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Average",
+			Value:  fmt.Sprintf("%.0f g (%+.1f%%)", price.AveragePrice, diff),
+			Inline: true,
+		})
+	}
+
+	if item.Healing > 0 && price.LowestSellPrice > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Healing",
+			Value:  fmt.Sprintf("%d HP, %.1f g/HP", item.Healing, price.LowestSellPrice/float64(item.Healing)),
+			Inline: true,
+		})
+	}
+
+	return embed
+}
+
+func priceAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+		return
+	}
+	if i.ApplicationCommandData().Name != "price" {
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: itemChoices(focusedValue(i.ApplicationCommandData().Options)),
+		},
+	})
+}
+
+// itemChoices builds up to 25 autocomplete choices from the item catalog.
+func itemChoices(current string) []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, it := range market.DefaultCatalog().Search(current, 25) {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  it.DisplayName(),
+			Value: it.Name,
+		})
+	}
+	return choices
+}
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxPriceAlertsPerUser caps how many alerts a single user can subscribe to.
+const maxPriceAlertsPerUser = 10
+
+var priceAlertCommand = &discordgo.ApplicationCommand{
+	Name:        "price-alert",
+	Description: "Get a DM when an item's market price crosses a threshold",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "add",
+			Description: "Subscribe to a price alert",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "item",
+					Description:  "The item to watch.",
+					Required:     true,
+					Autocomplete: true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "below",
+					Description: "Notify me when the lowest sell price drops to this many gold or less.",
+					Required:    false,
+					MinValue:    floatPtr(1),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "above",
+					Description: "Notify me when the lowest sell price rises to this many gold or more.",
+					Required:    false,
+					MinValue:    floatPtr(1),
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "List your price alerts",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "remove",
+			Description: "Remove one of your price alerts",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "id",
+					Description: "The alert number shown by /price-alert list.",
+					Required:    true,
+				},
+			},
+		},
+	},
+}
+
+func priceAlertHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "price-alert" {
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+
+	sub := data.Options[0]
+	opts := optionMap(sub.Options)
+	userID := interactionUserID(i)
+
+	switch sub.Name {
+	case "add":
+		priceAlertAdd(s, i, userID, opts)
+	case "list":
+		priceAlertList(s, i, userID)
+	case "remove":
+		priceAlertRemove(s, i, userID, opts)
+	}
+}
+
+func priceAlertAdd(s *discordgo.Session, i *discordgo.InteractionCreate, userID string, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	below, hasBelow := opts["below"]
+	above, hasAbove := opts["above"]
+	if hasBelow == hasAbove {
+		respondEphemeral(s, i, "Please provide exactly one of `below` or `above`.")
+		return
+	}
+
+	item, ok := resolveItem(opts["item"].StringValue())
+	if !ok {
+		respondEphemeral(s, i, fmt.Sprintf("Unknown item: %s", opts["item"].StringValue()))
+		return
+	}
+
+	direction, threshold := model.AlertBelow, below
+	if hasAbove {
+		direction, threshold = model.AlertAbove, above
+	}
+
+	count, err := model.CountPriceAlerts(DB, userID)
+	if err != nil {
+		interactionLogger(i).Error("failed to count alerts", "user", userID, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to save your price alert.")
+		return
+	}
+	if count >= maxPriceAlertsPerUser {
+		respondEphemeral(s, i, fmt.Sprintf("You already have %d price alerts. Remove one with `/price-alert remove` first.", count))
+		return
+	}
+
+	id, err := model.AddPriceAlert(DB, userID, item.ID, direction, float64(threshold.IntValue()))
+	if err != nil {
+		interactionLogger(i).Error("failed to add alert", "user", userID, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to save your price alert.")
+		return
+	}
+
+	respondEphemeral(s, i, fmt.Sprintf("🔔 Alert #%d: I'll DM you when **%s** is listed %s **%d g**.", id, item.DisplayName(), direction, threshold.IntValue()))
+}
+
+func priceAlertList(s *discordgo.Session, i *discordgo.InteractionCreate, userID string) {
+	alerts, err := model.ListPriceAlerts(DB, userID)
+	if err != nil {
+		interactionLogger(i).Error("failed to list alerts", "user", userID, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load your price alerts.")
+		return
+	}
+	if len(alerts) == 0 {
+		respondEphemeral(s, i, "You have no price alerts. Add one with `/price-alert add`.")
+		return
+	}
+
+	catalog := market.DefaultCatalog()
+	lines := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		lines = append(lines, fmt.Sprintf("`#%d` %s %s %.0f g", a.ID, catalog.DisplayName(a.ItemID), a.Direction, a.Threshold))
+	}
+
+	respondEphemeral(s, i, fmt.Sprintf("Your price alerts (%d/%d):\n%s", len(alerts), maxPriceAlertsPerUser, strings.Join(lines, "\n")))
+}
+
+func priceAlertRemove(s *discordgo.Session, i *discordgo.InteractionCreate, userID string, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	id := opts["id"].IntValue()
+
+	removed, err := model.DeletePriceAlert(DB, userID, id)
+	if err != nil {
+		interactionLogger(i).Error("failed to remove alert", "user", userID, "alert", id, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to remove the price alert.")
+		return
+	}
+	if !removed {
+		respondEphemeral(s, i, fmt.Sprintf("You have no alert #%d.", id))
+		return
+	}
+
+	respondEphemeral(s, i, fmt.Sprintf("Removed alert #%d.", id))
+}
+
+func priceAlertAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+		return
+	}
+	if i.ApplicationCommandData().Name != "price-alert" {
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: itemChoices(focusedValue(i.ApplicationCommandData().Options)),
+		},
+	})
+}
@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"klutco-lil-helper/internal/chart"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var priceHistoryCommand = &discordgo.ApplicationCommand{
+	Name:        "price-history",
+	Description: "Chart an item's market price over time",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "item",
+			Description:  "The item name (or its numeric ID).",
+			Required:     true,
+			Autocomplete: true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "range",
+			Description: "How far back to look (default 7 days).",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "7 days", Value: "7d"},
+				{Name: "30 days", Value: "30d"},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "just_for_me",
+			Description: "Only show the chart to me.",
+			Required:    false,
+		},
+	},
+}
+
+func priceHistoryHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "price-history" {
+		return
+	}
+
+	opts := optionMap(i.ApplicationCommandData().Options)
+	query := opts["item"].StringValue()
+
+	justForMe := false
+	if o, ok := opts["just_for_me"]; ok {
+		justForMe = o.BoolValue()
+	}
+	days := 7
+	if o, ok := opts["range"]; ok && o.StringValue() == "30d" {
+		days = 30
+	}
+
+	item, ok := resolveItem(query)
+	if !ok {
+		respondEphemeral(s, i, fmt.Sprintf("Unknown item: %s", query))
+		return
+	}
+
+	history, err := model.GetMarketPriceHistory(DB, item.ID, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		interactionLogger(i).Error("failed to load price history", "item", item.ID, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load the price history.")
+		return
+	}
+
+	png, err := renderPriceHistory(history)
+	if errors.Is(err, chart.ErrNoData) {
+		respondEphemeral(s, i, fmt.Sprintf("⚠️ No stored prices for %s in the last %d days yet.", item.DisplayName(), days))
+		return
+	}
+	if err != nil {
+		interactionLogger(i).Error("failed to render chart", "item", item.ID, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to draw the price chart.")
+		return
+	}
+
+	embed := formatPriceHistoryEmbed(item.DisplayName(), days, history)
+	files := attachChart(embed, "price-history.png", png)
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Files:  files,
+			Flags:  ephemeralFlag(justForMe),
+		},
+	})
+}
+
+// renderPriceHistory charts the lowest sell price (green) and average price (gold).
+func renderPriceHistory(history []model.MarketPrice) ([]byte, error) {
+	var lowest, average []chart.Point
+	for _, p := range history {
+		x := float64(p.Timestamp.Unix())
+		if p.LowestSellPrice > 0 {
+			lowest = append(lowest, chart.Point{X: x, Y: p.LowestSellPrice})
+		}
+		if p.AveragePrice > 0 {
+			average = append(average, chart.Point{X: x, Y: p.AveragePrice})
+		}
+	}
+
+	return chart.Line(chart.Options{
+		XLabel: func(x float64) string {
+			return time.Unix(int64(x), 0).UTC().Format("1/2")
+		},
+	},
+		chart.Series{Points: average, Color: chart.Gold},
+		chart.Series{Points: lowest, Color: chart.Green},
+	)
+}
+
+// formatPriceHistoryEmbed summarises the range shown on the chart.
+func formatPriceHistoryEmbed(name string, days int, history []model.MarketPrice) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s - last %d days", name, days),
+		Description: "🟩 lowest sell price · 🟨 average price",
+		Color:       0x00FF00, // Green
+		Fields:      []*discordgo.MessageEmbedField{},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%d stored snapshots", len(history)),
+		},
+	}
+
+	if len(history) == 0 {
+		return embed
+	}
+
+	low, high := history[0].LowestSellPrice, history[0].LowestSellPrice
+	for _, p := range history {
+		if p.LowestSellPrice < low {
+			low = p.LowestSellPrice
+		}
+		if p.LowestSellPrice > high {
+			high = p.LowestSellPrice
+		}
+	}
+	first, last := history[0].LowestSellPrice, history[len(history)-1].LowestSellPrice
+
+	embed.Fields = append(embed.Fields,
+		&discordgo.MessageEmbedField{Name: "Latest", Value: fmt.Sprintf("%.0f g", last), Inline: true},
+		&discordgo.MessageEmbedField{Name: "Low / High", Value: fmt.Sprintf("%.0f g / %.0f g", low, high), Inline: true},
+	)
+	if first > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Change",
+			Value:  fmt.Sprintf("%+.1f%%", (last-first)/first*100),
+			Inline: true,
+		})
+	}
+
+	return embed
+}
+
+func priceHistoryAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+		return
+	}
+	if i.ApplicationCommandData().Name != "price-history" {
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: itemChoices(focusedValue(i.ApplicationCommandData().Options)),
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"testing"
+
+	"klutco-lil-helper/internal/market"
+)
+
+func TestResolveItem(t *testing.T) {
+	for _, query := range []string{"Cooked Tuna", "cooked_tuna", "562", "#562"} {
+		if it, ok := resolveItem(query); !ok || it.ID != 562 {
+			t.Errorf("resolveItem(%q) = %+v, %v; want cooked_tuna", query, it, ok)
+		}
+	}
+	for _, query := range []string{"999999", "#999999", "not an item"} {
+		if it, ok := resolveItem(query); ok {
+			t.Errorf("resolveItem(%q) = %+v, want no match", query, it)
+		}
+	}
+}
+
+func TestFormatPriceEmbedFooter(t *testing.T) {
+	tuna, _ := market.DefaultCatalog().ByID(562)
+	embed := formatPriceEmbed(tuna, market.PriceItem{ItemID: 562, LowestSellPrice: 1450}, "Data from Idle Clans market API · 2m old")
+	if want := "Item #562 · Data from Idle Clans market API · 2m old"; embed.Footer.Text != want {
+		t.Errorf("footer = %q, want %q", embed.Footer.Text, want)
+	}
+}
@@ -0,0 +1,204 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+	"klutco-lil-helper/internal/ranksync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// rankSyncTimeout bounds a /ranks sync run, which looks up every linked member.
+const rankSyncTimeout = 2 * time.Minute
+
+var ranksCommand = &discordgo.ApplicationCommand{
+	Name:                     "ranks",
+	Description:              "Mirror in-game clan ranks onto Discord roles",
+	DefaultMemberPermissions: &adminPermissions,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "map",
+			Description: "Give linked members of a rank a role",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "rank",
+					Description: "The in-game rank",
+					Required:    true,
+					Choices:     rankChoices(),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionRole,
+					Name:        "role",
+					Description: "The Discord role for that rank",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "unmap",
+			Description: "Stop managing a rank's role",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "rank",
+					Description: "The in-game rank",
+					Required:    true,
+					Choices:     rankChoices(),
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "show",
+			Description: "Show which role each rank gets",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "sync",
+			Description: "Update members' roles from their in-game ranks now",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "dry_run",
+					Description: "Only report what would change",
+					Required:    false,
+				},
+			},
+		},
+	},
+}
+
+func rankChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(idleclans.Ranks))
+	for _, r := range idleclans.Ranks {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: r.String(), Value: int(r)})
+	}
+	return choices
+}
+
+func ranksHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "ranks" {
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	if i.GuildID == "" {
+		respondEphemeral(s, i, "Rank roles can only be set up in a server.")
+		return
+	}
+
+	sub := data.Options[0]
+	opts := optionMap(sub.Options)
+	switch sub.Name {
+	case "map":
+		ranksMap(s, i, opts)
+	case "unmap":
+		ranksUnmap(s, i, opts)
+	case "show":
+		ranksShow(s, i)
+	case "sync":
+		dryRun := false
+		if o, ok := opts["dry_run"]; ok {
+			dryRun = o.BoolValue()
+		}
+		ranksSync(s, i, dryRun)
+	}
+}
+
+func ranksMap(s *discordgo.Session, i *discordgo.InteractionCreate, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	rank := idleclans.Rank(opts["rank"].IntValue())
+	roleID := opts["role"].Value.(string)
+
+	if err := model.SetRankRole(DB, i.GuildID, int(rank), roleID); err != nil {
+		interactionLogger(i).Error("failed to save rank role", "rank", rank, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to save the rank role.")
+		return
+	}
+	respondEphemeral(s, i, fmt.Sprintf("✅ Linked %ss will get <@&%s>. Run `/ranks sync dry_run:true` to preview.", rank, roleID))
+}
+
+func ranksUnmap(s *discordgo.Session, i *discordgo.InteractionCreate, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	rank := idleclans.Rank(opts["rank"].IntValue())
+
+	removed, err := model.DeleteRankRole(DB, i.GuildID, int(rank))
+	if err != nil {
+		interactionLogger(i).Error("failed to delete rank role", "rank", rank, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to remove the rank role.")
+		return
+	}
+	if !removed {
+		respondEphemeral(s, i, fmt.Sprintf("%s has no role.", rank))
+		return
+	}
+	respondEphemeral(s, i, fmt.Sprintf("%s no longer has a managed role. Members keep the role they have.", rank))
+}
+
+func ranksShow(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	roles, err := model.ListRankRoles(DB, i.GuildID)
+	if err != nil {
+		interactionLogger(i).Error("failed to list rank roles", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load rank roles.")
+		return
+	}
+	respondEphemeral(s, i, formatRankRoles(roles))
+}
+
+func ranksSync(s *discordgo.Session, i *discordgo.InteractionCreate, dryRun bool) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		interactionLogger(i).Error("failed to acknowledge interaction", logging.Err(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rankSyncTimeout)
+	defer cancel()
+
+	report, err := ranksync.Sync(ctx, s, DB, idleclans.Default, model.ClanName, i.GuildID, dryRun, Logger.With(logging.KeySubsystem, "ranksync"))
+	content := ranksync.FormatReport(report)
+	if err != nil {
+		interactionLogger(i).Error("failed to sync rank roles", logging.Err(err))
+		content = "❌ Failed to sync rank roles."
+	}
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: strPtr(content),
+	}); err != nil {
+		interactionLogger(i).Error("failed to send rank sync report", logging.Err(err))
+	}
+}
+
+// formatRankRoles lists the role of every rank.
+func formatRankRoles(roles map[int]string) string {
+	if len(roles) == 0 {
+		return "No rank roles yet. Add one with `/ranks map`."
+	}
+	var sb strings.Builder
+	sb.WriteString("**Rank roles**")
+	for _, r := range idleclans.Ranks {
+		role := "not managed"
+		if id, ok := roles[int(r)]; ok {
+			role = "<@&" + id + ">"
+		}
+		sb.WriteString("\n• **" + r.String() + "** → " + role)
+	}
+	return sb.String()
+}
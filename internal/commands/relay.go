@@ -0,0 +1,298 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxListedFailedMessages caps how many dead-lettered messages /relay failed shows.
+	maxListedFailedMessages = 10
+	// maxFailedEmbedLen keeps the listing under Discord's 6000-character embed total,
+	// leaving room for the footer.
+	maxFailedEmbedLen = 5500
+)
+
+// adminPermissions hides admin commands from members who cannot manage the server.
+var adminPermissions int64 = discordgo.PermissionManageGuild
+
+var relayCommand = &discordgo.ApplicationCommand{
+	Name:                     "relay",
+	Description:              "Inspect and retry clan log lines that could not be posted",
+	DefaultMemberPermissions: &adminPermissions,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "failed",
+			Description: "List clan log lines that were given up on",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "requeue",
+			Description: "Retry failed clan log lines",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "id",
+					Description: "The message number shown by /relay failed. Omit to requeue all.",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "route",
+			Description: "Send a category of clan log lines to another channel, or drop it",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "category",
+					Description: "Which clan log lines to route",
+					Required:    true,
+					Choices:     eventCategoryChoices(),
+				},
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Where to post them",
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+					Required:     false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "drop",
+					Description: "Don't post them at all",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "routes",
+			Description: "Show where each category of clan log lines is posted",
+		},
+	},
+}
+
+// eventCategoryDescriptions names the clan log lines of each routing category.
+var eventCategoryDescriptions = map[model.EventCategory]string{
+	model.CategoryVault:     "Vault deposits & withdrawals",
+	model.CategoryRoster:    "Joins & leaves",
+	model.CategoryPromotion: "Promotions & demotions",
+	model.CategoryOther:     "Everything else",
+}
+
+func eventCategoryChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(model.EventCategories))
+	for _, c := range model.EventCategories {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  eventCategoryDescriptions[c],
+			Value: string(c),
+		})
+	}
+	return choices
+}
+
+func relayHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "relay" {
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+
+	sub := data.Options[0]
+	switch sub.Name {
+	case "failed":
+		relayFailed(s, i)
+	case "requeue":
+		relayRequeue(s, i, optionMap(sub.Options))
+	case "route":
+		relayRoute(s, i, optionMap(sub.Options))
+	case "routes":
+		relayRoutes(s, i)
+	}
+}
+
+func relayFailed(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	total, err := model.CountFailedMessages(DB)
+	if err != nil {
+		interactionLogger(i).Error("failed to count failed messages", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load failed messages.")
+		return
+	}
+	if total == 0 {
+		respondEphemeral(s, i, "✅ No failed clan log lines.")
+		return
+	}
+
+	msgs, err := model.ListFailedMessages(DB, maxListedFailedMessages)
+	if err != nil {
+		interactionLogger(i).Error("failed to list failed messages", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load failed messages.")
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{formatFailedMessagesEmbed(msgs, total)},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func relayRequeue(s *discordgo.Session, i *discordgo.InteractionCreate, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	if o, ok := opts["id"]; ok {
+		id := o.IntValue()
+		requeued, err := model.RequeueFailedMessage(DB, id)
+		if err != nil {
+			interactionLogger(i).Error("failed to requeue message", logging.KeyMessageID, id, logging.Err(err))
+			respondEphemeral(s, i, "❌ Failed to requeue the message.")
+			return
+		}
+		if !requeued {
+			respondEphemeral(s, i, fmt.Sprintf("Message #%d is not in the failed list.", id))
+			return
+		}
+		respondEphemeral(s, i, fmt.Sprintf("🔁 Requeued message #%d.", id))
+		return
+	}
+
+	n, err := model.RequeueAllFailedMessages(DB)
+	if err != nil {
+		interactionLogger(i).Error("failed to requeue messages", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to requeue the messages.")
+		return
+	}
+	respondEphemeral(s, i, fmt.Sprintf("🔁 Requeued %d message(s).", n))
+}
+
+func relayRoute(s *discordgo.Session, i *discordgo.InteractionCreate, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	if i.GuildID == "" {
+		respondEphemeral(s, i, "Routing rules can only be set in a server.")
+		return
+	}
+
+	category, ok := model.ParseEventCategory(opts["category"].StringValue())
+	if !ok {
+		respondEphemeral(s, i, "❌ Unknown category.")
+		return
+	}
+	route := model.RelayRoute{GuildID: i.GuildID, Category: category}
+	if o, ok := opts["channel"]; ok {
+		route.ChannelID = o.Value.(string)
+	}
+	if o, ok := opts["drop"]; ok {
+		route.Drop = o.BoolValue()
+	}
+
+	logger := interactionLogger(i).With("category", category)
+	switch {
+	case route.Drop && route.ChannelID != "":
+		respondEphemeral(s, i, "❌ Pick a channel or drop, not both.")
+		return
+	case !route.Drop && route.ChannelID == "":
+		if _, err := model.DeleteRelayRoute(DB, i.GuildID, category); err != nil {
+			logger.Error("failed to delete relay route", logging.Err(err))
+			respondEphemeral(s, i, "❌ Failed to update the routing rule.")
+			return
+		}
+		respondEphemeral(s, i, "↩️ "+describeRelayRoute(category, route, false))
+		return
+	}
+
+	if err := model.SetRelayRoute(DB, route); err != nil {
+		logger.Error("failed to save relay route", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to update the routing rule.")
+		return
+	}
+	respondEphemeral(s, i, "✅ "+describeRelayRoute(category, route, true))
+}
+
+func relayRoutes(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.GuildID == "" {
+		respondEphemeral(s, i, "Routing rules can only be set in a server.")
+		return
+	}
+
+	routes, err := model.ListRelayRoutes(DB, i.GuildID)
+	if err != nil {
+		interactionLogger(i).Error("failed to list relay routes", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load routing rules.")
+		return
+	}
+	respondEphemeral(s, i, formatRelayRoutes(routes))
+}
+
+// formatRelayRoutes lists where every category is posted, including unrouted ones.
+func formatRelayRoutes(routes map[model.EventCategory]model.RelayRoute) string {
+	var sb strings.Builder
+	sb.WriteString("**Clan log routing**")
+	for _, c := range model.EventCategories {
+		r, ok := routes[c]
+		sb.WriteString("\n• " + describeRelayRoute(c, r, ok))
+	}
+	return sb.String()
+}
+
+func describeRelayRoute(c model.EventCategory, r model.RelayRoute, routed bool) string {
+	label := "**" + eventCategoryDescriptions[c] + "** → "
+	switch {
+	case routed && r.Drop:
+		return label + "dropped"
+	case routed && r.ChannelID != "":
+		return label + "<#" + r.ChannelID + ">"
+	}
+	return label + "relay channel"
+}
+
+// formatFailedMessagesEmbed lists dead-lettered messages with their last error.
+func formatFailedMessagesEmbed(msgs []model.ClanMessage, total int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:  "Failed Clan Log Lines",
+		Color:  0xFF0000, // Red
+		Fields: []*discordgo.MessageEmbedField{},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Retry with /relay requeue",
+		},
+	}
+
+	size := utf8.RuneCountInString(embed.Title)
+	for _, m := range msgs {
+		field := &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("#%d · %s", m.ID, m.Timestamp.UTC().Format("Jan _2 15:04 UTC")),
+			Value: fmt.Sprintf("%s\n❌ %d attempts: `%s`", truncate(m.Message, 150), m.Attempts, truncate(m.LastError, 200)),
+		}
+		size += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+		if size > maxFailedEmbedLen {
+			break
+		}
+		embed.Fields = append(embed.Fields, field)
+	}
+
+	if shown := len(embed.Fields); total > shown {
+		embed.Footer.Text = fmt.Sprintf("Showing %d of %d · %s", shown, total, embed.Footer.Text)
+	}
+	return embed
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(strings.TrimSpace(s))
+	if len(r) <= n {
+		return string(r)
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"klutco-lil-helper/internal/model"
+)
+
+func TestFormatFailedMessagesEmbed(t *testing.T) {
+	msgs := []model.ClanMessage{{
+		ID:        42,
+		Message:   strings.Repeat("x", 250),
+		Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		State:     model.DeliveryFailed,
+		Attempts:  8,
+		LastError: "HTTP 400 Bad Request",
+	}}
+
+	embed := formatFailedMessagesEmbed(msgs, 3)
+
+	if len(embed.Fields) != 1 {
+		t.Fatalf("got %d fields, want 1", len(embed.Fields))
+	}
+	f := embed.Fields[0]
+	if !strings.HasPrefix(f.Name, "#42 ") {
+		t.Errorf("field name = %q, want message number first", f.Name)
+	}
+	if !strings.Contains(f.Value, "8 attempts") || !strings.Contains(f.Value, "HTTP 400") {
+		t.Errorf("field value = %q, want attempts and last error", f.Value)
+	}
+	if !strings.Contains(f.Value, "…") {
+		t.Errorf("long message not truncated: %q", f.Value)
+	}
+	if !strings.HasPrefix(embed.Footer.Text, "Showing 1 of 3") {
+		t.Errorf("footer = %q, want count of hidden messages", embed.Footer.Text)
+	}
+}
+
+func TestFormatFailedMessagesEmbedFitsDiscordLimit(t *testing.T) {
+	msgs := make([]model.ClanMessage, maxListedFailedMessages)
+	for n := range msgs {
+		msgs[n] = model.ClanMessage{
+			ID:        int64(n + 1),
+			Message:   strings.Repeat("é", 500),
+			Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+			Attempts:  8,
+			LastError: strings.Repeat("e", 1000),
+		}
+	}
+
+	embed := formatFailedMessagesEmbed(msgs, 100)
+
+	size := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Footer.Text)
+	for _, f := range embed.Fields {
+		size += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+	}
+	if size > 6000 {
+		t.Errorf("embed is %d characters, over Discord's 6000 limit", size)
+	}
+}
+
+func TestFormatRelayRoutes(t *testing.T) {
+	routes := map[model.EventCategory]model.RelayRoute{
+		model.CategoryVault:     {Category: model.CategoryVault, ChannelID: "123"},
+		model.CategoryPromotion: {Category: model.CategoryPromotion, Drop: true},
+	}
+
+	got := formatRelayRoutes(routes)
+
+	for _, want := range []string{
+		"**Vault deposits & withdrawals** → <#123>",
+		"**Joins & leaves** → relay channel",
+		"**Promotions & demotions** → dropped",
+		"**Everything else** → relay channel",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("routes listing lacks %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// rosterTTL is how long the clan member list is reused for autocomplete.
+	rosterTTL = 10 * time.Minute
+	// rosterRetryAfter is how long a failed fetch is not retried, so an API outage doesn't
+	// cost every autocomplete keystroke a request.
+	rosterRetryAfter = 1 * time.Minute
+	// rosterFetchTimeout keeps autocomplete within Discord's three second deadline.
+	rosterFetchTimeout = 2 * time.Second
+)
+
+// roster caches the clan member names offered by autocomplete.
+var roster struct {
+	mu         sync.Mutex
+	names      []string
+	fetchedAt  time.Time
+	failedAt   time.Time
+	refreshing bool
+}
+
+// rosterNames returns the clan's member names, refreshed from the Idle Clans API at most every rosterTTL.
+// An expired list is served while a single background fetch replaces it; only the very first
+// call waits on the API. When the API is unavailable it keeps the previous list, or falls back
+// to the linked members, and waits rosterRetryAfter before trying again.
+func rosterNames() []string {
+	roster.mu.Lock()
+	names := roster.names
+	fresh := names != nil && time.Since(roster.fetchedAt) < rosterTTL
+	fetch := !fresh && !roster.refreshing && time.Since(roster.failedAt) >= rosterRetryAfter
+	if fetch {
+		roster.refreshing = true
+	}
+	roster.mu.Unlock()
+
+	switch {
+	case fresh:
+		return names
+	case fetch && names == nil:
+		if fetched := refreshRoster(); fetched != nil {
+			return fetched
+		}
+	case fetch:
+		go refreshRoster()
+	}
+
+	if names != nil {
+		return names
+	}
+	return linkedMemberNames()
+}
+
+// refreshRoster fetches the clan's member names into the cache, returning nil when the fetch fails.
+func refreshRoster() []string {
+	ctx, cancel := context.WithTimeout(context.Background(), rosterFetchTimeout)
+	defer cancel()
+
+	clan, err := idleclans.Default.ClanInfo(ctx, model.ClanName)
+
+	roster.mu.Lock()
+	defer roster.mu.Unlock()
+	roster.refreshing = false
+	if err != nil {
+		Logger.Warn("failed to fetch clan roster", logging.Err(err))
+		roster.failedAt = time.Now()
+		return nil
+	}
+
+	names := make([]string, 0, len(clan.Members))
+	for _, m := range clan.Members {
+		names = append(names, m.Name)
+	}
+	sort.Slice(names, func(i, j int) bool { return strings.ToLower(names[i]) < strings.ToLower(names[j]) })
+	roster.names, roster.fetchedAt = names, time.Now()
+	return names
+}
+
+func linkedMemberNames() []string {
+	if DB == nil {
+		return nil
+	}
+	linked, err := model.ListLinkedMembers(DB)
+	if err != nil {
+		Logger.Warn("failed to list linked members", logging.Err(err))
+		return nil
+	}
+	names := make([]string, 0, len(linked))
+	for name := range linked {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return strings.ToLower(names[i]) < strings.ToLower(names[j]) })
+	return names
+}
+
+// memberChoices builds up to 25 autocomplete choices of names containing current.
+func memberChoices(names []string, current string) []*discordgo.ApplicationCommandOptionChoice {
+	current = strings.ToLower(strings.TrimSpace(current))
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, name := range names {
+		if current != "" && !strings.Contains(strings.ToLower(name), current) {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+		if len(choices) == 25 {
+			break
+		}
+	}
+	return choices
+}
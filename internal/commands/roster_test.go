@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+)
+
+func TestRosterNamesBacksOffAndRefreshesInBackground(t *testing.T) {
+	var requests int32
+	var failing atomic.Bool
+	release := make(chan struct{})
+	var hold atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if hold.Load() {
+			<-release
+		}
+		_, _ = w.Write([]byte(`{"clanName":"KlutzCo","memberlist":[{"memberName":"yothos"},{"memberName":"ImaKlutz"}]}`))
+	}))
+	defer srv.Close()
+
+	saved := idleclans.Default
+	idleclans.Default = idleclans.NewClient(srv.URL + "/api/")
+	t.Cleanup(func() {
+		idleclans.Default = saved
+		roster.names, roster.fetchedAt, roster.failedAt, roster.refreshing = nil, time.Time{}, time.Time{}, false
+	})
+
+	// a failed fetch is not retried on the next keystroke
+	failing.Store(true)
+	if names := rosterNames(); len(names) != 0 {
+		t.Errorf("names with the API down and nothing linked = %v", names)
+	}
+	rosterNames()
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("%d requests while backing off, want 1", n)
+	}
+
+	failing.Store(false)
+	roster.failedAt = time.Now().Add(-rosterRetryAfter)
+	if names := rosterNames(); len(names) != 2 || names[0] != "ImaKlutz" {
+		t.Fatalf("names = %v, want the sorted roster", names)
+	}
+
+	// an expired list is served at once while one background fetch replaces it
+	hold.Store(true)
+	roster.fetchedAt = time.Now().Add(-rosterTTL)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if names := rosterNames(); len(names) != 2 {
+			t.Errorf("names during refresh = %v", names)
+		}
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("autocomplete waited %v on the refresh", waited)
+	}
+	close(release)
+
+	for i := 0; i < 100; i++ {
+		roster.mu.Lock()
+		done := !roster.refreshing
+		roster.mu.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("%d requests after the background refresh, want 3", n)
+	}
+	if time.Since(roster.fetchedAt) > time.Minute {
+		t.Error("background refresh did not update the roster")
+	}
+}
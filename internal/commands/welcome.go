@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"strings"
+
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var welcomeCommand = &discordgo.ApplicationCommand{
+	Name:                     "welcome",
+	Description:              "Configure welcome and farewell posts for clan joins and leaves",
+	DefaultMemberPermissions: &adminPermissions,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "setup",
+			Description: "Turn on welcome and farewell posts",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Where to post them",
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+					Required:     true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionRole,
+					Name:        "role",
+					Description: "Role given to linked members while they are in the clan",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "welcome",
+					Description: "Welcome message. {member} and {clan} are filled in.",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "farewell",
+					Description: "Farewell message. {member} and {clan} are filled in.",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "show",
+			Description: "Show the current welcome settings",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "disable",
+			Description: "Turn off welcome and farewell posts",
+		},
+	},
+}
+
+func welcomeHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "welcome" {
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+	if i.GuildID == "" {
+		respondEphemeral(s, i, "Welcome posts can only be set up in a server.")
+		return
+	}
+
+	sub := data.Options[0]
+	switch sub.Name {
+	case "setup":
+		welcomeSetup(s, i, optionMap(sub.Options))
+	case "show":
+		welcomeShow(s, i)
+	case "disable":
+		welcomeDisable(s, i)
+	}
+}
+
+func welcomeSetup(s *discordgo.Session, i *discordgo.InteractionCreate, opts map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	settings := model.WelcomeSettings{
+		GuildID:   i.GuildID,
+		ChannelID: opts["channel"].Value.(string),
+	}
+	if o, ok := opts["role"]; ok {
+		settings.RoleID = o.Value.(string)
+	}
+	if o, ok := opts["welcome"]; ok {
+		settings.WelcomeMessage = strings.TrimSpace(o.StringValue())
+	}
+	if o, ok := opts["farewell"]; ok {
+		settings.FarewellMessage = strings.TrimSpace(o.StringValue())
+	}
+
+	if err := model.SetWelcomeSettings(DB, settings); err != nil {
+		interactionLogger(i).Error("failed to save welcome settings", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to save the welcome settings.")
+		return
+	}
+	respondEphemeral(s, i, "✅ Welcome posts are on.\n"+formatWelcomeSettings(settings))
+}
+
+func welcomeShow(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	settings, ok, err := model.GetWelcomeSettings(DB, i.GuildID)
+	if err != nil {
+		interactionLogger(i).Error("failed to load welcome settings", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load the welcome settings.")
+		return
+	}
+	if !ok {
+		respondEphemeral(s, i, "Welcome posts are off. Turn them on with `/welcome setup`.")
+		return
+	}
+	respondEphemeral(s, i, formatWelcomeSettings(settings))
+}
+
+func welcomeDisable(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if _, err := model.DeleteWelcomeSettings(DB, i.GuildID); err != nil {
+		interactionLogger(i).Error("failed to delete welcome settings", logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to turn off welcome posts.")
+		return
+	}
+	respondEphemeral(s, i, "Welcome posts are off.")
+}
+
+// formatWelcomeSettings describes a guild's welcome settings, showing defaults for unset messages.
+func formatWelcomeSettings(settings model.WelcomeSettings) string {
+	role := "none"
+	if settings.RoleID != "" {
+		role = "<@&" + settings.RoleID + ">"
+	}
+	welcome := settings.WelcomeMessage
+	if welcome == "" {
+		welcome = "(default)"
+	}
+	farewell := settings.FarewellMessage
+	if farewell == "" {
+		farewell = "(default)"
+	}
+
+	return "**Channel:** <#" + settings.ChannelID + ">\n" +
+		"**Clan role:** " + role + "\n" +
+		"**Welcome:** " + welcome + "\n" +
+		"**Farewell:** " + farewell
+}
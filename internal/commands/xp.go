@@ -0,0 +1,276 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// xpLeaderboardSize is how many players /xp gains ranks.
+const xpLeaderboardSize = 10
+
+// xpPeriods maps the period option to how many days it covers.
+var xpPeriods = map[string]int{
+	"week":  7,
+	"month": 30,
+}
+
+var xpPeriodOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "period",
+	Description: "How far back to look (default week)",
+	Required:    false,
+	Choices: []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "Week", Value: "week"},
+		{Name: "Month", Value: "month"},
+	},
+}
+
+var xpSkillOption = &discordgo.ApplicationCommandOption{
+	Type:         discordgo.ApplicationCommandOptionString,
+	Name:         "skill",
+	Description:  "Only count this skill (default all skills)",
+	Required:     false,
+	Autocomplete: true,
+}
+
+var xpCommand = &discordgo.ApplicationCommand{
+	Name:        "xp",
+	Description: "Skill XP gains of linked clan members",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "gains",
+			Description: "Leaderboard of XP gained",
+			Options:     []*discordgo.ApplicationCommandOption{xpSkillOption, xpPeriodOption},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "history",
+			Description: "A player's daily XP gains",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "name",
+					Description:  "The in-game name. Defaults to your linked account.",
+					Required:     false,
+					Autocomplete: true,
+				},
+				xpSkillOption,
+				xpPeriodOption,
+			},
+		},
+	},
+}
+
+func xpHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommand {
+		return
+	}
+	if i.ApplicationCommandData().Name != "xp" {
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return
+	}
+
+	sub := data.Options[0]
+	opts := optionMap(sub.Options)
+	skill := ""
+	if o, ok := opts["skill"]; ok {
+		skill = strings.ToLower(strings.TrimSpace(o.StringValue()))
+	}
+	period := "week"
+	if o, ok := opts["period"]; ok {
+		if _, known := xpPeriods[o.StringValue()]; known {
+			period = o.StringValue()
+		}
+	}
+	since := time.Now().UTC().AddDate(0, 0, -xpPeriods[period])
+
+	switch sub.Name {
+	case "gains":
+		xpGains(s, i, skill, period, since)
+	case "history":
+		name := ""
+		if o, ok := opts["name"]; ok {
+			name = strings.TrimSpace(o.StringValue())
+		}
+		xpHistory(s, i, name, skill, period, since)
+	}
+}
+
+func xpGains(s *discordgo.Session, i *discordgo.InteractionCreate, skill, period string, since time.Time) {
+	gains, err := model.XPGains(DB, skill, since)
+	if err != nil {
+		interactionLogger(i).Error("failed to load xp gains", "skill", skill, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load XP gains.")
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{formatXPGainsEmbed(gains, skill, period)},
+		},
+	})
+}
+
+func xpHistory(s *discordgo.Session, i *discordgo.InteractionCreate, name, skill, period string, since time.Time) {
+	if name == "" {
+		linked, ok, err := model.LinkedGameName(DB, interactionUserID(i))
+		if err != nil {
+			interactionLogger(i).Error("failed to look up linked member", logging.Err(err))
+			respondEphemeral(s, i, "❌ Failed to look up your linked account.")
+			return
+		}
+		if !ok {
+			respondEphemeral(s, i, "Give a player name, or link your account with `/link` first.")
+			return
+		}
+		name = linked
+	}
+
+	points, err := model.XPHistory(DB, name, skill, since)
+	if err != nil {
+		interactionLogger(i).Error("failed to load xp history", "player", name, logging.Err(err))
+		respondEphemeral(s, i, "❌ Failed to load XP history.")
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{formatXPHistoryEmbed(name, points, skill, period)},
+		},
+	})
+}
+
+func xpAutocompleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionApplicationCommandAutocomplete {
+		return
+	}
+	if i.ApplicationCommandData().Name != "xp" {
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	if focusedOptionName(data.Options) == "name" {
+		choices = memberChoices(rosterNames(), focusedValue(data.Options))
+	} else {
+		skills, err := model.ListXPSkills(DB)
+		if err != nil {
+			interactionLogger(i).Error("failed to list xp skills", logging.Err(err))
+		}
+		current := strings.ToLower(focusedValue(data.Options))
+		for _, skill := range skills {
+			if current != "" && !strings.Contains(skill, current) {
+				continue
+			}
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  idleclans.Skill{Name: skill}.DisplayName(),
+				Value: skill,
+			})
+			if len(choices) == 25 {
+				break
+			}
+		}
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: choices,
+		},
+	})
+}
+
+// focusedOptionName returns the name of the focused option, looking inside subcommands.
+func focusedOptionName(options []*discordgo.ApplicationCommandInteractionDataOption) string {
+	for _, o := range options {
+		if o.Focused {
+			return o.Name
+		}
+		if name := focusedOptionName(o.Options); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// xpScope describes the skill a leaderboard or history covers.
+func xpScope(skill string) string {
+	if skill == "" {
+		return "All skills"
+	}
+	return idleclans.Skill{Name: skill}.DisplayName()
+}
+
+// formatXPGainsEmbed ranks players by XP gained over the period.
+func formatXPGainsEmbed(gains []model.XPGain, skill, period string) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("📈 XP gains this %s · %s", period, xpScope(skill)),
+		Color: 0x00FF00, // Green
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "From daily snapshots of linked members · /link to join in",
+		},
+	}
+
+	var lines []string
+	medals := []string{"🥇", "🥈", "🥉"}
+	for _, g := range gains {
+		if g.Gain <= 0 || len(lines) == xpLeaderboardSize {
+			continue
+		}
+		place := fmt.Sprintf("%d.", len(lines)+1)
+		if len(lines) < len(medals) {
+			place = medals[len(lines)]
+		}
+		lines = append(lines, fmt.Sprintf("%s **%s** +%s XP", place, g.GameName, formatXP(g.Gain)))
+	}
+	if len(lines) == 0 {
+		embed.Description = "No XP gained yet. Snapshots are taken daily, so check back tomorrow."
+		return embed
+	}
+	embed.Description = strings.Join(lines, "\n")
+	return embed
+}
+
+// formatXPHistoryEmbed lists a player's XP gained on each day of the period.
+func formatXPHistoryEmbed(name string, points []model.XPPoint, skill, period string) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("📊 %s · %s this %s", name, xpScope(skill), period),
+		Color: 0x5865F2, // Blurple
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "From daily snapshots",
+		},
+	}
+	if len(points) < 2 {
+		embed.Description = "Not enough snapshots yet. They are taken daily for linked members."
+		return embed
+	}
+
+	var sb strings.Builder
+	sb.WriteString("```\n")
+	for j := 1; j < len(points); j++ {
+		fmt.Fprintf(&sb, "%s  +%s\n", points[j].Day.Format("Jan _2"), formatXP(points[j].XP-points[j-1].XP))
+	}
+	sb.WriteString("```")
+	embed.Description = sb.String()
+
+	total := points[len(points)-1].XP - points[0].XP
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: "Total", Value: "+" + formatXP(total) + " XP", Inline: true},
+		{Name: "Now", Value: formatXP(points[len(points)-1].XP) + " XP", Inline: true},
+	}
+	return embed
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"klutco-lil-helper/internal/model"
+)
+
+func TestFormatXPGainsEmbed(t *testing.T) {
+	gains := []model.XPGain{
+		{GameName: "ImaKlutz", Gain: 2500000},
+		{GameName: "Lazy", Gain: 0},
+		{GameName: "Bob", Gain: 1200},
+	}
+
+	embed := formatXPGainsEmbed(gains, "woodcutting", "week")
+
+	if !strings.Contains(embed.Title, "Woodcutting") {
+		t.Errorf("title = %q", embed.Title)
+	}
+	want := "🥇 **ImaKlutz** +2.5M XP\n🥈 **Bob** +1.2K XP"
+	if embed.Description != want {
+		t.Errorf("description = %q, want %q", embed.Description, want)
+	}
+
+	empty := formatXPGainsEmbed(nil, "", "month")
+	if !strings.Contains(empty.Title, "All skills") || !strings.Contains(empty.Description, "No XP gained") {
+		t.Errorf("empty embed = %q / %q", empty.Title, empty.Description)
+	}
+}
+
+func TestFormatXPHistoryEmbed(t *testing.T) {
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	points := []model.XPPoint{
+		{Day: day, XP: 1000},
+		{Day: day.AddDate(0, 0, 1), XP: 3000},
+		{Day: day.AddDate(0, 0, 2), XP: 3500},
+	}
+
+	embed := formatXPHistoryEmbed("ImaKlutz", points, "", "week")
+
+	if !strings.Contains(embed.Description, "Oct  2  +2.0K") || !strings.Contains(embed.Description, "Oct  3  +500") {
+		t.Errorf("description = %q", embed.Description)
+	}
+	if got := embed.Fields[0].Value; got != "+2.5K XP" {
+		t.Errorf("total = %q", got)
+	}
+
+	if single := formatXPHistoryEmbed("ImaKlutz", points[:1], "", "week"); !strings.Contains(single.Description, "Not enough") {
+		t.Errorf("single snapshot description = %q", single.Description)
+	}
+}
@@ -0,0 +1,324 @@
+// Package digest builds the weekly clan digest: vault deposits, roster changes, boss poll
+// participation and market moves of the previous Monday-to-Monday week.
+package digest
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"klutco-lil-helper/internal/bosssummary"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/market"
+	"klutco-lil-helper/internal/model"
+	"klutco-lil-helper/internal/numfmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	topItems       = 8   // vault items listed by deposit count
+	topDonors      = 5   // contributors listed
+	marketMoves    = 3   // rises and falls listed
+	marketMinPrice = 100 // ignore items cheaper than this, as the daily market movers do
+	// maxFieldLen is Discord's limit on an embed field value.
+	maxFieldLen = 1024
+	// marketSlack is how far from the start or end of the week a raw snapshot may be and
+	// still stand in for it, matching the daily market movers.
+	marketSlack = 4 * time.Hour
+)
+
+// BossCount is the poll participation of one boss.
+type BossCount struct {
+	bosssummary.Boss
+	model.BossParticipation
+}
+
+// Digest is what happened in the clan over one week.
+type Digest struct {
+	Since, Until time.Time
+	Vault        model.VaultSummary
+	Joined, Left []string
+	Bosses       []BossCount // poll order; bosses nobody reacted to are left out
+	Rises, Falls []market.Move
+	// MarketCompared is false when there were no prices close enough to both ends of the week.
+	MarketCompared bool
+}
+
+// Week returns the last full Monday-to-Monday week before now in loc.
+func Week(now time.Time, loc *time.Location) (since, until time.Time) {
+	local := now.In(loc)
+	daysSinceMonday := (int(local.Weekday()) + 6) % 7
+	until = time.Date(local.Year(), local.Month(), local.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+	return until.AddDate(0, 0, -7), until
+}
+
+// Load collects the digest of [since, until).
+func Load(db *sql.DB, since, until time.Time) (Digest, error) {
+	d := Digest{Since: since, Until: until}
+
+	var err error
+	if d.Vault, err = model.SummarizeVaultBetween(db, since, until); err != nil {
+		return Digest{}, fmt.Errorf("summarize vault: %w", err)
+	}
+	if d.Joined, d.Left, err = model.RosterChangesBetween(db, since, until); err != nil {
+		return Digest{}, fmt.Errorf("roster changes: %w", err)
+	}
+
+	participation, err := model.BossPollParticipation(db, since, until)
+	if err != nil {
+		return Digest{}, fmt.Errorf("boss participation: %w", err)
+	}
+	for _, boss := range bosssummary.Bosses() {
+		if p, ok := participation[boss.Emoji]; ok {
+			d.Bosses = append(d.Bosses, BossCount{Boss: boss, BossParticipation: p})
+		}
+	}
+
+	previous, err := marketBaseline(db, since)
+	if err != nil {
+		return Digest{}, fmt.Errorf("market snapshot: %w", err)
+	}
+	latestAt, latest, err := model.GetMarketSnapshotAt(db, until)
+	if err != nil {
+		return Digest{}, fmt.Errorf("market snapshot: %w", err)
+	}
+	if len(previous) > 0 && len(latest) > 0 && until.Sub(latestAt) <= marketSlack {
+		d.Rises, d.Falls = market.Movers(previous, model.LowestSellPrices(latest), marketMinPrice, marketMoves)
+		d.MarketCompared = true
+	}
+	return d, nil
+}
+
+// marketBaseline returns the lowest sell prices at the start of the week: a raw snapshot
+// within marketSlack of since, or else the daily rollup of since's UTC day once its raw
+// snapshots have been downsampled. It returns nil when neither exists.
+func marketBaseline(db *sql.DB, since time.Time) (map[int]float64, error) {
+	beforeAt, before, err := model.GetMarketSnapshotAt(db, since)
+	if err != nil {
+		return nil, err
+	}
+	afterAt, after, err := model.GetMarketSnapshotAfter(db, since)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(before) > 0 && since.Sub(beforeAt) <= marketSlack &&
+		(len(after) == 0 || since.Sub(beforeAt) <= afterAt.Sub(since)):
+		return model.LowestSellPrices(before), nil
+	case len(after) > 0 && afterAt.Sub(since) <= marketSlack:
+		return model.LowestSellPrices(after), nil
+	}
+	daily, err := model.GetMarketDailyPrices(db, since)
+	if err != nil || len(daily) == 0 {
+		return nil, err
+	}
+	return model.LowestSellPrices(daily), nil
+}
+
+// Build loads and formats the digest of the last full week before now.
+func Build(db *sql.DB, now time.Time) (*discordgo.MessageEmbed, error) {
+	loc := easternLocation()
+	since, until := Week(now, loc)
+	d, err := Load(db, since, until)
+	if err != nil {
+		return nil, err
+	}
+	return FormatEmbed(d, market.DefaultCatalog()), nil
+}
+
+// Publish posts the digest of the last full week to a channel. With regenerate set it
+// edits the channel's latest digest instead, posting a new one only if that fails.
+func Publish(s *discordgo.Session, db *sql.DB, channelID string, now time.Time, regenerate bool, logger *slog.Logger) error {
+	if s == nil || db == nil {
+		return fmt.Errorf("session or db is nil")
+	}
+	if logger == nil {
+		logger = logging.Discard()
+	}
+	logger = logger.With(logging.KeyChannel, channelID)
+
+	embed, err := Build(db, now)
+	if err != nil {
+		return err
+	}
+
+	if regenerate {
+		oldMsgID, err := model.GetScheduledMessage(db, model.MessageTypeDigest, channelID)
+		if err != nil {
+			return fmt.Errorf("get digest message: %w", err)
+		}
+		if oldMsgID != "" {
+			if _, err := s.ChannelMessageEditEmbed(channelID, oldMsgID, embed); err == nil {
+				return nil
+			}
+			logger.Warn("failed to edit digest, posting a new one", logging.KeyMessageID, oldMsgID, logging.Err(err))
+		}
+	}
+
+	m, err := s.ChannelMessageSendEmbed(channelID, embed)
+	if err != nil {
+		return fmt.Errorf("send digest: %w", err)
+	}
+	if err := model.UpsertScheduledMessage(db, model.MessageTypeDigest, channelID, m.ID); err != nil {
+		logger.Error("failed to store digest message ID", logging.KeyMessageID, m.ID, logging.Err(err))
+	}
+	return nil
+}
+
+// FormatEmbed renders a digest.
+func FormatEmbed(d Digest, catalog *market.Catalog) *discordgo.MessageEmbed {
+	last := d.Until.AddDate(0, 0, -1)
+	return &discordgo.MessageEmbed{
+		Title:       "📰 Weekly Clan Digest",
+		Description: fmt.Sprintf("%s – %s", d.Since.Format("Jan 2"), last.Format("Jan 2, 2006")),
+		Color:       0xFFD700, // Gold color
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "🏦 Vault deposits", Value: formatVaultDeposits(d.Vault)},
+			{Name: "🏅 Top donors", Value: formatTopDonors(d.Vault.Contributors)},
+			{Name: "👋 Roster", Value: formatRoster(d.Joined, d.Left)},
+			{Name: "⚔️ Boss participation", Value: formatBosses(d.Bosses)},
+			{Name: "📊 Market moves", Value: formatMarketMoves(catalog, d)},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: model.ClanName + " · Sign-ups from the boss polls · Prices from the Idle Clans market API",
+		},
+	}
+}
+
+func formatVaultDeposits(v model.VaultSummary) string {
+	var lines []string
+	if v.GoldDeposited > 0 {
+		lines = append(lines, fmt.Sprintf("💰 %s gold", numfmt.Thousands(v.GoldDeposited)))
+	}
+
+	items := make([]string, 0, len(v.ItemDeposits))
+	for item := range v.ItemDeposits {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := v.ItemDeposits[items[i]], v.ItemDeposits[items[j]]
+		if a != b {
+			return a > b
+		}
+		return items[i] < items[j]
+	})
+	for i, item := range items {
+		if i == topItems {
+			lines = append(lines, fmt.Sprintf("…and %d more items", len(items)-topItems))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("📦 %s ×%s", item, numfmt.Thousands(v.ItemDeposits[item])))
+	}
+
+	if len(lines) == 0 {
+		return "No deposits this week"
+	}
+	return joinField(lines)
+}
+
+func formatTopDonors(contributors []model.VaultContribution) string {
+	if len(contributors) == 0 {
+		return "No donors this week"
+	}
+	medals := []string{"🥇", "🥈", "🥉"}
+	var lines []string
+	for i, c := range contributors {
+		if i == topDonors {
+			break
+		}
+		place := fmt.Sprintf("%d.", i+1)
+		if i < len(medals) {
+			place = medals[i]
+		}
+		var parts []string
+		if c.Gold > 0 {
+			parts = append(parts, numfmt.Thousands(c.Gold)+" gold")
+		}
+		if c.Items > 0 {
+			parts = append(parts, numfmt.Thousands(c.Items)+" items")
+		}
+		lines = append(lines, fmt.Sprintf("%s **%s** %s", place, c.Player, strings.Join(parts, " + ")))
+	}
+	return joinField(lines)
+}
+
+func formatRoster(joined, left []string) string {
+	if len(joined) == 0 && len(left) == 0 {
+		return "No roster changes"
+	}
+	var lines []string
+	if len(joined) > 0 {
+		lines = append(lines, fmt.Sprintf("Joined (%d): %s", len(joined), strings.Join(joined, ", ")))
+	}
+	if len(left) > 0 {
+		lines = append(lines, fmt.Sprintf("Left (%d): %s", len(left), strings.Join(left, ", ")))
+	}
+	return joinField(lines)
+}
+
+func formatBosses(bosses []BossCount) string {
+	if len(bosses) == 0 {
+		return "No boss poll sign-ups"
+	}
+	lines := make([]string, 0, len(bosses))
+	for _, b := range bosses {
+		lines = append(lines, fmt.Sprintf("%s %s: %s · %s",
+			b.Emoji, b.Name, plural(b.SignUps, "sign-up"), plural(b.Members, "member")))
+	}
+	return joinField(lines)
+}
+
+func formatMarketMoves(catalog *market.Catalog, d Digest) string {
+	if !d.MarketCompared {
+		return "Not enough price history for this week"
+	}
+	if len(d.Rises) == 0 && len(d.Falls) == 0 {
+		return "Nothing notable"
+	}
+	var lines []string
+	for _, moves := range [][]market.Move{d.Rises, d.Falls} {
+		for _, m := range moves {
+			icon := "📈"
+			if m.Change < 0 {
+				icon = "📉"
+			}
+			lines = append(lines, fmt.Sprintf("%s **%s** %s → %s g (%+.1f%%)", icon,
+				catalog.DisplayName(m.ItemID), numfmt.Thousands(int64(m.Previous)), numfmt.Thousands(int64(m.Current)), m.Change*100))
+		}
+	}
+	return joinField(lines)
+}
+
+// joinField joins lines, cutting them off to fit an embed field.
+func joinField(lines []string) string {
+	s := strings.Join(lines, "\n")
+	if len(s) <= maxFieldLen {
+		return s
+	}
+	cut := strings.LastIndex(s[:maxFieldLen-len("\n…")], "\n")
+	if cut < 0 {
+		return s[:maxFieldLen-len("…")] + "…"
+	}
+	return s[:cut] + "\n…"
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return strconv.Itoa(n) + " " + word + "s"
+}
+
+// easternLocation returns the America/New_York zone the digest weeks are counted in.
+func easternLocation() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return loc
+}
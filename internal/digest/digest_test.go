@@ -0,0 +1,156 @@
+package digest
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"klutco-lil-helper/internal/market"
+	"klutco-lil-helper/internal/model"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestWeek(t *testing.T) {
+	loc := time.FixedZone("EST", -5*60*60)
+	wantSince := time.Date(2026, 3, 2, 0, 0, 0, 0, loc)
+	wantUntil := time.Date(2026, 3, 9, 0, 0, 0, 0, loc)
+
+	for _, now := range []time.Time{
+		time.Date(2026, 3, 9, 8, 0, 0, 0, loc),       // Monday morning digest
+		time.Date(2026, 3, 11, 15, 0, 0, 0, loc),     // regenerated on Wednesday
+		time.Date(2026, 3, 16, 4, 0, 0, 0, time.UTC), // still Sunday in loc
+	} {
+		since, until := Week(now, loc)
+		if !since.Equal(wantSince) || !until.Equal(wantUntil) {
+			t.Errorf("Week(%s) = %s..%s", now, since, until)
+		}
+	}
+}
+
+func TestLoadAndFormat(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	if err := model.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	since := time.Date(2026, 3, 2, 5, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 0, 7)
+	in := since.Add(24 * time.Hour)
+
+	for i, text := range []string{
+		"guildan added 1000000x Gold.",
+		"guildan added 2x Godly key.",
+		"yothos added 10x Krono's book.",
+	} {
+		if _, err := model.InsertClanMessage(db, model.ClanMessage{ClanName: model.ClanName, MemberUsername: "x", Message: text, Timestamp: in.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = model.RecordMemberJoined(db, "newbie", in)
+	_ = model.RecordMemberLeft(db, "quitter", in)
+	_ = model.RecordMemberJoined(db, "veteran", since.AddDate(0, -1, 0))
+	_ = model.RecordBossPollReaction(db, "poll", "😈", "1", in)
+	_ = model.RecordBossPollReaction(db, "poll", "😈", "2", in)
+	_ = model.InsertMarketSnapshot(db, since.Add(time.Hour), []model.MarketPrice{{ItemID: 1, LowestSellPrice: 1000}, {ItemID: 2, LowestSellPrice: 500}})
+	_ = model.InsertMarketSnapshot(db, until.Add(-time.Hour), []model.MarketPrice{{ItemID: 1, LowestSellPrice: 1500}, {ItemID: 2, LowestSellPrice: 400}})
+
+	d, err := Load(db, since, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Joined) != 1 || d.Joined[0] != "newbie" || len(d.Left) != 1 || d.Left[0] != "quitter" {
+		t.Errorf("roster = %v, %v", d.Joined, d.Left)
+	}
+	if len(d.Bosses) != 1 || d.Bosses[0].Name != "Hades" || d.Bosses[0].SignUps != 2 {
+		t.Errorf("bosses = %+v", d.Bosses)
+	}
+	if len(d.Rises) != 1 || d.Rises[0].ItemID != 1 || len(d.Falls) != 1 || d.Falls[0].ItemID != 2 {
+		t.Errorf("moves = %+v, %+v", d.Rises, d.Falls)
+	}
+
+	embed := FormatEmbed(d, market.NewCatalog(nil))
+	if embed.Description != "Mar 2 – Mar 8, 2026" {
+		t.Errorf("description = %q", embed.Description)
+	}
+	fields := make(map[string]string)
+	for _, f := range embed.Fields {
+		fields[f.Name] = f.Value
+	}
+	checks := map[string]string{
+		"🏦 Vault deposits":      "💰 1,000,000 gold\n📦 Krono's book ×10\n📦 Godly key ×2",
+		"🏅 Top donors":          "🥇 **guildan** 1,000,000 gold + 2 items\n🥈 **yothos** 10 items",
+		"👋 Roster":              "Joined (1): newbie\nLeft (1): quitter",
+		"⚔️ Boss participation": "😈 Hades: 2 sign-ups · 2 members",
+		"📊 Market moves":        "📈 **Item #1** 1,000 → 1,500 g (+50.0%)\n📉 **Item #2** 500 → 400 g (-20.0%)",
+	}
+	for name, want := range checks {
+		if fields[name] != want {
+			t.Errorf("%s = %q, want %q", name, fields[name], want)
+		}
+	}
+
+	empty := FormatEmbed(Digest{Since: since, Until: until}, market.NewCatalog(nil))
+	for _, f := range empty.Fields {
+		if f.Value == "" {
+			t.Errorf("empty digest field %s has no value", f.Name)
+		}
+	}
+}
+
+func TestLoadMarketBaseline(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	if err := model.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	since := time.Date(2026, 3, 2, 5, 0, 0, 0, time.UTC)
+	until := since.AddDate(0, 0, 7)
+	_ = model.InsertMarketSnapshot(db, since.Add(2*24*time.Hour), []model.MarketPrice{{ItemID: 1, LowestSellPrice: 2000}})
+	_ = model.InsertMarketSnapshot(db, until.Add(-time.Hour), []model.MarketPrice{{ItemID: 1, LowestSellPrice: 2200}})
+
+	// the only snapshot near the start of the week is days late, so there is nothing to compare
+	d, err := Load(db, since, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.MarketCompared || len(d.Rises) != 0 {
+		t.Errorf("compared against a late snapshot: %+v", d.Rises)
+	}
+
+	// once the first day has been rolled up, its daily average is the baseline
+	_ = model.InsertMarketSnapshot(db, since.Add(8*time.Hour), []model.MarketPrice{{ItemID: 1, LowestSellPrice: 1000}})
+	_ = model.InsertMarketSnapshot(db, since.Add(12*time.Hour), []model.MarketPrice{{ItemID: 1, LowestSellPrice: 1200}})
+	if err := model.DownsampleMarketPrices(db, since.Add(24*time.Hour), since.AddDate(0, 0, -30)); err != nil {
+		t.Fatal(err)
+	}
+	d, err = Load(db, since, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.MarketCompared || len(d.Rises) != 1 || d.Rises[0].Previous != 1100 || d.Rises[0].Current != 2200 {
+		t.Errorf("rises = %+v, want 1100 → 2200 from the daily rollup", d.Rises)
+	}
+}
+
+func TestJoinField(t *testing.T) {
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", 20)
+	}
+	got := joinField(lines)
+	if len(got) > maxFieldLen || !strings.HasSuffix(got, "\n…") {
+		t.Errorf("joinField length %d, suffix %q", len(got), got[len(got)-5:])
+	}
+}
@@ -0,0 +1,93 @@
+package idleclans
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Rank is a member's rank within their clan.
+type Rank int
+
+const (
+	RankMember  Rank = 0
+	RankOfficer Rank = 1
+	RankLeader  Rank = 2
+)
+
+// Ranks lists the known ranks from lowest to highest.
+var Ranks = []Rank{RankMember, RankOfficer, RankLeader}
+
+func (r Rank) String() string {
+	switch r {
+	case RankMember:
+		return "Member"
+	case RankOfficer:
+		return "Officer"
+	case RankLeader:
+		return "Leader"
+	}
+	return fmt.Sprintf("Rank %d", int(r))
+}
+
+// ClanMember is one entry of a clan's member list.
+type ClanMember struct {
+	Name string `json:"memberName"`
+	Rank Rank   `json:"rank"`
+}
+
+// Clan is the public information of a clan.
+type Clan struct {
+	Name               string       `json:"clanName"`
+	Tag                string       `json:"tag"`
+	Members            []ClanMember `json:"memberlist"`
+	MinimumTotalLevel  int          `json:"minimumTotalLevelRequired"`
+	IsRecruiting       bool         `json:"isRecruiting"`
+	Language           string       `json:"language"`
+	Category           string       `json:"category"`
+	RecruitmentMessage string       `json:"recruitmentMessage"`
+	SerializedUpgrades string       `json:"serializedUpgrades"` // JSON array of unlocked upgrade IDs
+}
+
+// UpgradeCount returns how many clan upgrades are unlocked, or -1 when the API didn't say.
+func (c *Clan) UpgradeCount() int {
+	if c.SerializedUpgrades == "" {
+		return -1
+	}
+	var upgrades []json.RawMessage
+	if err := json.Unmarshal([]byte(c.SerializedUpgrades), &upgrades); err != nil {
+		return -1
+	}
+	return len(upgrades)
+}
+
+// RankCounts returns how many members hold each rank.
+func (c *Clan) RankCounts() map[Rank]int {
+	counts := make(map[Rank]int)
+	for _, m := range c.Members {
+		counts[m.Rank]++
+	}
+	return counts
+}
+
+// MembersByRank returns the clan's members, highest rank first and then by name.
+func (c *Clan) MembersByRank() []ClanMember {
+	members := append([]ClanMember(nil), c.Members...)
+	sort.SliceStable(members, func(i, j int) bool {
+		if members[i].Rank != members[j].Rank {
+			return members[i].Rank > members[j].Rank
+		}
+		return members[i].Name < members[j].Name
+	})
+	return members
+}
+
+// ClanInfo fetches a clan's public information and member list.
+func (c *Client) ClanInfo(ctx context.Context, name string) (*Clan, error) {
+	var clan Clan
+	if err := c.getJSON(ctx, "/Clan/recruitment/"+pathName(name), &clan); err != nil {
+		return nil, err
+	}
+	return &clan, nil
+}
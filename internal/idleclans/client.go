@@ -0,0 +1,73 @@
+// Package idleclans is a small client for the public Idle Clans query API.
+package idleclans
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the root of the public Idle Clans query API.
+const DefaultBaseURL = "https://query.idleclans.com/api"
+
+// ErrNotFound is returned when the API has no clan or player with the requested name.
+var ErrNotFound = errors.New("idleclans: not found")
+
+// Default is the client used against the live API.
+var Default = NewClient(DefaultBaseURL)
+
+// Client calls the Idle Clans query API.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient returns a client for the API rooted at baseURL, e.g. an httptest server in tests.
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// getJSON fetches path below the base URL and decodes the JSON response into v.
+func (c *Client) getJSON(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("idleclans: %s returned status %d", path, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	// the API answers unknown names with an empty body rather than a 404
+	if len(strings.TrimSpace(string(body))) == 0 || string(body) == "null" {
+		return ErrNotFound
+	}
+	return json.Unmarshal(body, v)
+}
+
+// pathName escapes a clan or player name for use as a path segment.
+func pathName(name string) string {
+	return url.PathEscape(strings.TrimSpace(name))
+}
@@ -0,0 +1,120 @@
+package idleclans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newFixtureServer serves testdata files by request path.
+func newFixtureServer(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, ok := routes[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Errorf("read fixture: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClanInfo(t *testing.T) {
+	srv := newFixtureServer(t, map[string]string{
+		"/api/Clan/recruitment/KlutzCo": "testdata/clan_info.json",
+	})
+	c := NewClient(srv.URL + "/api/")
+
+	clan, err := c.ClanInfo(context.Background(), "KlutzCo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clan.Name != "KlutzCo" || clan.Tag != "KLZ" || !clan.IsRecruiting || clan.MinimumTotalLevel != 500 {
+		t.Errorf("clan = %+v", clan)
+	}
+
+	if n := clan.UpgradeCount(); n != 4 {
+		t.Errorf("UpgradeCount = %d, want 4", n)
+	}
+	if counts := clan.RankCounts(); counts[RankMember] != 2 || counts[RankLeader] != 1 {
+		t.Errorf("RankCounts = %v", counts)
+	}
+
+	members := clan.MembersByRank()
+	if len(members) != 4 {
+		t.Fatalf("got %d members, want 4", len(members))
+	}
+	want := []ClanMember{{"ImaKlutz", RankLeader}, {"guildan", RankOfficer}, {"moraxam", RankMember}, {"yothos", RankMember}}
+	for i, m := range members {
+		if m != want[i] {
+			t.Errorf("member %d = %+v, want %+v", i, m, want[i])
+		}
+	}
+}
+
+func TestClanInfoNotFound(t *testing.T) {
+	srv := newFixtureServer(t, nil)
+	c := NewClient(srv.URL + "/api")
+
+	if _, err := c.ClanInfo(context.Background(), "Nobody Here"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPlayerProfile(t *testing.T) {
+	srv := newFixtureServer(t, map[string]string{
+		"/api/Player/profile/ImaKlutz": "testdata/player_profile.json",
+	})
+	c := NewClient(srv.URL + "/api")
+
+	p, err := c.PlayerProfile(context.Background(), "ImaKlutz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Username != "ImaKlutz" || p.ClanName != "KlutzCo" || p.GameModeName() != "Group Ironman" {
+		t.Errorf("player = %+v, mode %q", p, p.GameModeName())
+	}
+
+	var got []string
+	for _, s := range p.Skills() {
+		got = append(got, fmt.Sprintf("%s:%d", s.DisplayName(), s.Level))
+	}
+	want := "Attack:99 Defence:1 Rigour:2 Strength:10 Woodcutting:120"
+	if strings.Join(got, " ") != want {
+		t.Errorf("skills = %s, want %s", strings.Join(got, " "), want)
+	}
+	if p.TotalLevel() != 99+1+2+10+120 {
+		t.Errorf("total level = %d", p.TotalLevel())
+	}
+	if p.TotalXP() != 13034431+1154+104273167.5+83 {
+		t.Errorf("total xp = %f", p.TotalXP())
+	}
+}
+
+func TestLevelForXP(t *testing.T) {
+	tests := []struct {
+		xp   float64
+		want int
+	}{
+		{0, 1}, {82, 1}, {83, 2}, {13034430, 98}, {13034431, 99}, {104273167, 120}, {1e12, 120},
+	}
+	for _, tt := range tests {
+		if got := LevelForXP(tt.xp); got != tt.want {
+			t.Errorf("LevelForXP(%v) = %d, want %d", tt.xp, got, tt.want)
+		}
+	}
+}
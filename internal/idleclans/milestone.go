@@ -0,0 +1,84 @@
+package idleclans
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// SkillMilestoneStep is the skill level interval worth celebrating.
+	SkillMilestoneStep = 10
+	// TotalMilestoneStep is the total level interval worth celebrating.
+	TotalMilestoneStep = 250
+)
+
+// Milestone is a level a player reached in a skill, or in total when Skill is "".
+type Milestone struct {
+	Skill string
+	Level int
+}
+
+// IsTotal reports whether the milestone is a total level threshold.
+func (m Milestone) IsTotal() bool {
+	return m.Skill == ""
+}
+
+// IsMax reports whether the milestone is the maximum level of a skill.
+func (m Milestone) IsMax() bool {
+	return !m.IsTotal() && m.Level >= MaxLevel
+}
+
+// String describes the milestone, e.g. "level 50 Woodcutting".
+func (m Milestone) String() string {
+	switch {
+	case m.IsTotal():
+		return fmt.Sprintf("total level %d", m.Level)
+	case m.IsMax():
+		return fmt.Sprintf("max level %s", Skill{Name: m.Skill}.DisplayName())
+	}
+	return fmt.Sprintf("level %d %s", m.Level, Skill{Name: m.Skill}.DisplayName())
+}
+
+// Milestones returns the milestones crossed between two experience snapshots: every
+// SkillMilestoneStep levels of a skill (including MaxLevel) and every TotalMilestoneStep
+// total levels. Only the highest milestone of each skill and of the total is returned, so
+// a long gap between snapshots doesn't announce every level on the way. Skills missing
+// from before are skipped rather than counted from level 1.
+func Milestones(before, after map[string]float64) []Milestone {
+	var milestones []Milestone
+	totalBefore, totalAfter := 0, 0
+
+	names := make([]string, 0, len(after))
+	for name := range after {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prevXP, ok := before[name]
+		if !ok {
+			continue
+		}
+		from, to := LevelForXP(prevXP), LevelForXP(after[name])
+		totalBefore += from
+		totalAfter += to
+
+		if reached := highestStep(from, to, SkillMilestoneStep); reached > 0 {
+			milestones = append(milestones, Milestone{Skill: name, Level: reached})
+		}
+	}
+
+	if reached := highestStep(totalBefore, totalAfter, TotalMilestoneStep); reached > 0 {
+		milestones = append(milestones, Milestone{Level: reached})
+	}
+	return milestones
+}
+
+// highestStep returns the highest multiple of step in (from, to], or 0 if there is none.
+func highestStep(from, to, step int) int {
+	reached := to / step * step
+	if reached <= from {
+		return 0
+	}
+	return reached
+}
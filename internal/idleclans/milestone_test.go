@@ -0,0 +1,47 @@
+package idleclans
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMilestones(t *testing.T) {
+	xp := func(level int) float64 { return levelXP[level-1] }
+
+	before := map[string]float64{
+		"attack":      xp(49),
+		"woodcutting": xp(119),
+		"mining":      xp(12),
+		"fishing":     xp(30),
+	}
+	after := map[string]float64{
+		"attack":      xp(72),  // 50, 60 and 70 crossed; only 70 is announced
+		"woodcutting": xp(120), // max level
+		"mining":      xp(19),  // no multiple of 10
+		"fishing":     xp(30),  // unchanged
+		"cooking":     xp(90),  // new skill, skipped
+	}
+
+	got := Milestones(before, after)
+	want := []Milestone{
+		{Skill: "attack", Level: 70},
+		{Skill: "woodcutting", Level: 120},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Milestones = %+v, want %+v", got, want)
+	}
+	if got[1].String() != "max level Woodcutting" || got[0].String() != "level 70 Attack" {
+		t.Errorf("strings = %q, %q", got[0], got[1])
+	}
+
+	// total 249 -> 251
+	before = map[string]float64{"attack": xp(99), "mining": xp(99), "fishing": xp(51)}
+	after = map[string]float64{"attack": xp(99), "mining": xp(99), "fishing": xp(53)}
+	got = Milestones(before, after)
+	if want := []Milestone{{Level: 250}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("total Milestones = %+v, want %+v", got, want)
+	}
+	if got[0].String() != "total level 250" {
+		t.Errorf("total string = %q", got[0])
+	}
+}
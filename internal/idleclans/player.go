@@ -0,0 +1,99 @@
+package idleclans
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// MaxLevel is the highest skill level.
+const MaxLevel = 120
+
+var titleizer = cases.Title(language.Und)
+
+// levelXP[l] is the experience needed for level l+1; Idle Clans uses the classic
+// RuneScape curve, extended to MaxLevel.
+var levelXP = func() []float64 {
+	xp := make([]float64, MaxLevel)
+	points := 0.0
+	for l := 1; l < MaxLevel; l++ {
+		points += math.Floor(float64(l) + 300*math.Pow(2, float64(l)/7))
+		xp[l] = math.Floor(points / 4)
+	}
+	return xp
+}()
+
+// LevelForXP returns the skill level reached with the given experience.
+func LevelForXP(xp float64) int {
+	return sort.Search(MaxLevel, func(i int) bool { return levelXP[i] > xp })
+}
+
+// Player is a player's public profile.
+type Player struct {
+	Username         string             `json:"username"`
+	GameMode         string             `json:"gameMode"`
+	ClanName         string             `json:"guildName"`
+	SkillExperiences map[string]float64 `json:"skillExperiences"`
+}
+
+// Skill is a player's experience and level in one skill.
+type Skill struct {
+	Name  string
+	XP    float64
+	Level int
+}
+
+// DisplayName returns the human readable skill name, e.g. "Rigour".
+func (s Skill) DisplayName() string {
+	return titleizer.String(strings.ReplaceAll(s.Name, "_", " "))
+}
+
+// Skills returns the player's skills in alphabetical order.
+func (p *Player) Skills() []Skill {
+	skills := make([]Skill, 0, len(p.SkillExperiences))
+	for name, xp := range p.SkillExperiences {
+		skills = append(skills, Skill{Name: name, XP: xp, Level: LevelForXP(xp)})
+	}
+	sort.Slice(skills, func(i, j int) bool { return skills[i].Name < skills[j].Name })
+	return skills
+}
+
+// TotalXP returns the experience summed over every skill.
+func (p *Player) TotalXP() float64 {
+	total := 0.0
+	for _, xp := range p.SkillExperiences {
+		total += xp
+	}
+	return total
+}
+
+// TotalLevel returns the levels summed over every skill.
+func (p *Player) TotalLevel() int {
+	total := 0
+	for _, xp := range p.SkillExperiences {
+		total += LevelForXP(xp)
+	}
+	return total
+}
+
+// GameModeName returns the human readable game mode, e.g. "Group Ironman".
+func (p *Player) GameModeName() string {
+	switch p.GameMode {
+	case "", "default":
+		return "Standard"
+	}
+	return titleizer.String(strings.ReplaceAll(p.GameMode, "_", " "))
+}
+
+// PlayerProfile fetches a player's public profile.
+func (c *Client) PlayerProfile(ctx context.Context, name string) (*Player, error) {
+	var p Player
+	if err := c.getJSON(ctx, "/Player/profile/"+pathName(name), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
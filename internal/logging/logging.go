@@ -0,0 +1,72 @@
+// Package logging builds the structured logger shared by every subsystem.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Field keys used consistently across subsystems.
+const (
+	KeySubsystem = "subsystem"
+	KeyGuild     = "guild"
+	KeyChannel   = "channel"
+	KeyMessageID = "message_id"
+	KeyError     = "error"
+)
+
+// New creates a logger writing to w at the given level, as "json" or "text" (the default).
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+	if strings.EqualFold(format, "json") {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// FromEnv creates a stderr logger configured by LOG_LEVEL (debug, info, warn, error)
+// and LOG_FORMAT (text, json).
+func FromEnv() *slog.Logger {
+	level, err := ParseLevel(os.Getenv("LOG_LEVEL"))
+	logger := New(os.Stderr, level, os.Getenv("LOG_FORMAT"))
+	if err != nil {
+		logger.Warn("invalid LOG_LEVEL, using info", KeyError, err)
+	}
+	return logger
+}
+
+// ParseLevel parses a level name; an empty string is info.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
+
+// For returns the logger for a subsystem, or a discarding logger when l is nil.
+func For(l *slog.Logger, subsystem string) *slog.Logger {
+	if l == nil {
+		return Discard()
+	}
+	return l.With(KeySubsystem, subsystem)
+}
+
+// Discard returns a logger that drops everything, for tests and unconfigured callers.
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// Err formats an error as a log attribute.
+func Err(err error) slog.Attr {
+	return slog.Any(KeyError, err)
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"", slog.LevelInfo, false},
+		{"debug", slog.LevelDebug, false},
+		{"WARN", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"loud", slog.LevelInfo, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v, err %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNewJSONWithSubsystem(t *testing.T) {
+	var buf bytes.Buffer
+	logger := For(New(&buf, slog.LevelInfo, "json"), "clanlogs")
+
+	logger.Debug("hidden")
+	logger.Warn("fetch failed", KeyChannel, "123", Err(errors.New("boom")))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1 (debug filtered):\n%s", len(lines), buf.String())
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"level":      "WARN",
+		"msg":        "fetch failed",
+		KeySubsystem: "clanlogs",
+		KeyChannel:   "123",
+		KeyError:     "boom",
+	} {
+		if entry[key] != want {
+			t.Errorf("%s = %v, want %q", key, entry[key], want)
+		}
+	}
+}
+
+func TestForNilLogger(t *testing.T) {
+	// must not panic
+	For(nil, "x").Info("dropped")
+}
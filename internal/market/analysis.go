@@ -0,0 +1,86 @@
+package market
+
+import "sort"
+
+// Deal is an item listed below its average price.
+type Deal struct {
+	ItemID          int
+	LowestSellPrice float64
+	AveragePrice    float64
+	Discount        float64 // fraction below average, e.g. 0.25 for 25% off
+}
+
+// FindDeals returns items whose lowest sell price is at least minDiscount below
+// their average price, biggest discount first.
+func FindDeals(items []PriceItem, minDiscount float64) []Deal {
+	var deals []Deal
+	for _, it := range items {
+		if it.AveragePrice <= 0 || it.LowestSellPrice <= 0 {
+			continue
+		}
+		discount := (it.AveragePrice - it.LowestSellPrice) / it.AveragePrice
+		if discount < minDiscount || discount <= 0 {
+			continue
+		}
+		deals = append(deals, Deal{
+			ItemID:          it.ItemID,
+			LowestSellPrice: it.LowestSellPrice,
+			AveragePrice:    it.AveragePrice,
+			Discount:        discount,
+		})
+	}
+
+	sort.Slice(deals, func(i, j int) bool {
+		if deals[i].Discount != deals[j].Discount {
+			return deals[i].Discount > deals[j].Discount
+		}
+		return deals[i].ItemID < deals[j].ItemID
+	})
+	return deals
+}
+
+// Move is the price change of an item between two snapshots.
+type Move struct {
+	ItemID   int
+	Previous float64
+	Current  float64
+	Change   float64 // relative change, e.g. 0.1 for +10%
+}
+
+// Movers compares two price maps and returns up to n of the biggest relative rises and falls.
+// Items whose previous price is below minPrice are ignored so cheap items do not dominate.
+func Movers(previous, current map[int]float64, minPrice float64, n int) (rises, falls []Move) {
+	for id, cur := range current {
+		prev, ok := previous[id]
+		if !ok || prev <= 0 || cur <= 0 || prev < minPrice || prev == cur {
+			continue
+		}
+		m := Move{ItemID: id, Previous: prev, Current: cur, Change: (cur - prev) / prev}
+		if m.Change > 0 {
+			rises = append(rises, m)
+		} else {
+			falls = append(falls, m)
+		}
+	}
+
+	sort.Slice(rises, func(i, j int) bool {
+		if rises[i].Change != rises[j].Change {
+			return rises[i].Change > rises[j].Change
+		}
+		return rises[i].ItemID < rises[j].ItemID
+	})
+	sort.Slice(falls, func(i, j int) bool {
+		if falls[i].Change != falls[j].Change {
+			return falls[i].Change < falls[j].Change
+		}
+		return falls[i].ItemID < falls[j].ItemID
+	})
+
+	if len(rises) > n {
+		rises = rises[:n]
+	}
+	if len(falls) > n {
+		falls = falls[:n]
+	}
+	return rises, falls
+}
@@ -0,0 +1,43 @@
+package market
+
+import "testing"
+
+func TestFindDeals(t *testing.T) {
+	items := []PriceItem{
+		{ItemID: 1, LowestSellPrice: 50, AveragePrice: 100},  // 50% off
+		{ItemID: 2, LowestSellPrice: 90, AveragePrice: 100},  // 10% off
+		{ItemID: 3, LowestSellPrice: 120, AveragePrice: 100}, // above average
+		{ItemID: 4, LowestSellPrice: 70, AveragePrice: 100},  // 30% off
+		{ItemID: 5, LowestSellPrice: 10, AveragePrice: 0},    // no average
+	}
+
+	deals := FindDeals(items, 0.2)
+	if len(deals) != 2 {
+		t.Fatalf("len(deals) = %d, want 2: %+v", len(deals), deals)
+	}
+	if deals[0].ItemID != 1 || deals[1].ItemID != 4 {
+		t.Errorf("deals order = %d, %d, want 1, 4", deals[0].ItemID, deals[1].ItemID)
+	}
+	if deals[0].Discount != 0.5 {
+		t.Errorf("deals[0].Discount = %v, want 0.5", deals[0].Discount)
+	}
+}
+
+func TestMovers(t *testing.T) {
+	previous := map[int]float64{1: 100, 2: 100, 3: 100, 4: 5, 5: 100}
+	current := map[int]float64{1: 150, 2: 80, 3: 100, 4: 50, 5: 110, 6: 999}
+
+	rises, falls := Movers(previous, current, 10, 5)
+
+	if len(rises) != 2 || rises[0].ItemID != 1 || rises[1].ItemID != 5 {
+		t.Errorf("rises = %+v, want items 1 then 5", rises)
+	}
+	if len(falls) != 1 || falls[0].ItemID != 2 || falls[0].Change != -0.2 {
+		t.Errorf("falls = %+v, want item 2 at -20%%", falls)
+	}
+
+	rises, _ = Movers(previous, current, 10, 1)
+	if len(rises) != 1 {
+		t.Errorf("limit 1 returned %d rises", len(rises))
+	}
+}
@@ -0,0 +1,177 @@
+package market
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"klutco-lil-helper/internal/logging"
+)
+
+const (
+	// DefaultCacheTTL is how long fetched prices are served without asking the API again.
+	DefaultCacheTTL = 5 * time.Minute
+	// fetchTimeout bounds a single shared fetch, independent of the caller that started it.
+	fetchTimeout = 45 * time.Second
+	// defaultStaleWait is how long Get waits on a refresh before serving expired data, so
+	// commands answer within Discord's 3 second deadline while the API is slow or down.
+	defaultStaleWait = 1500 * time.Millisecond
+)
+
+// Snapshot is a set of market prices and when they were fetched.
+type Snapshot struct {
+	Items     []PriceItem
+	FetchedAt time.Time
+	Stale     bool // true when the API failed and older data is being served
+}
+
+// Age returns how old the snapshot is.
+func (s Snapshot) Age() time.Duration {
+	return time.Since(s.FetchedAt)
+}
+
+// LowestSellPrices indexes the snapshot's lowest sell prices by item ID.
+func (s Snapshot) LowestSellPrices() map[int]float64 {
+	return LowestSellPrices(s.Items)
+}
+
+// FetchFunc fetches the current market prices.
+type FetchFunc func(ctx context.Context) ([]PriceItem, error)
+
+// Cache serves market prices from memory, refreshing them at most once per TTL.
+// Concurrent refreshes are collapsed into a single API call, and when the API is down
+// the last good data is served with Stale set.
+type Cache struct {
+	ttl       time.Duration
+	staleWait time.Duration
+	fetch     FetchFunc
+	now       func() time.Time
+
+	mu       sync.Mutex
+	data     *Snapshot
+	inflight *fetchCall
+}
+
+// fetchCall is a fetch in progress that other callers can wait on.
+type fetchCall struct {
+	done chan struct{}
+	snap Snapshot
+	err  error
+}
+
+// NewCache creates a cache around fetch. A ttl <= 0 uses DefaultCacheTTL.
+func NewCache(ttl time.Duration, fetch FetchFunc) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Cache{ttl: ttl, staleWait: defaultStaleWait, fetch: fetch, now: time.Now}
+}
+
+// Prices is the shared cache used by commands and background jobs.
+var Prices = NewCache(DefaultCacheTTL, FetchLatestPrices)
+
+// SetTTL changes how long fetched prices stay fresh.
+func (c *Cache) SetTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	c.ttl = ttl
+	c.mu.Unlock()
+}
+
+// Get returns fresh prices, fetching them if the cached copy is older than the TTL.
+// If older data exists and the fetch fails or takes longer than a moment, that data is
+// returned at once with Stale set and a nil error while the fetch carries on in the background.
+func (c *Cache) Get(ctx context.Context) (Snapshot, error) {
+	c.mu.Lock()
+	if c.data != nil && c.now().Sub(c.data.FetchedAt) < c.ttl {
+		snap := *c.data
+		c.mu.Unlock()
+		return snap, nil
+	}
+	hasData, wait := c.data != nil, c.staleWait
+	c.mu.Unlock()
+
+	if !hasData {
+		return c.Refresh(ctx)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	snap, err := c.Refresh(waitCtx)
+	if err == nil {
+		return snap, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.data != nil {
+		stale := *c.data
+		stale.Stale = true
+		return stale, nil
+	}
+	return Snapshot{}, err
+}
+
+// Refresh fetches prices now, sharing the API call with any refresh already in flight.
+func (c *Cache) Refresh(ctx context.Context) (Snapshot, error) {
+	c.mu.Lock()
+	call := c.inflight
+	if call == nil {
+		call = &fetchCall{done: make(chan struct{})}
+		c.inflight = call
+		go c.doFetch(call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.snap, call.err
+	case <-ctx.Done():
+		return Snapshot{}, ctx.Err()
+	}
+}
+
+// doFetch runs the shared fetch detached from any single caller's context,
+// so one impatient command cannot fail the fetch for everybody else.
+func (c *Cache) doFetch(call *fetchCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	items, err := c.fetch(ctx)
+
+	c.mu.Lock()
+	if err == nil {
+		c.data = &Snapshot{Items: items, FetchedAt: c.now()}
+		call.snap = *c.data
+	}
+	call.err = err
+	c.inflight = nil
+	c.mu.Unlock()
+
+	close(call.done)
+}
+
+// Run refreshes the cache every interval until ctx is cancelled, keeping it warm for commands.
+// Failed refreshes are logged to logger.
+func (c *Cache) Run(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	if interval <= 0 {
+		interval = c.ttl
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+			logger.Warn("background refresh failed", logging.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
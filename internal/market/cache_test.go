@@ -0,0 +1,164 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for cache tests.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestCacheServesFreshData(t *testing.T) {
+	var calls int32
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	c := NewCache(time.Minute, func(ctx context.Context) ([]PriceItem, error) {
+		atomic.AddInt32(&calls, 1)
+		return []PriceItem{{ItemID: 1, LowestSellPrice: 10}}, nil
+	})
+	c.now = clock.Now
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times within TTL, want 1", calls)
+	}
+
+	clock.Advance(2 * time.Minute)
+	if _, err := c.Get(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("fetch called %d times after TTL, want 2", calls)
+	}
+}
+
+func TestCacheSingleFlight(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	c := NewCache(time.Minute, func(ctx context.Context) ([]PriceItem, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return []PriceItem{{ItemID: 1}}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Get(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	// wait until the single fetch is in flight, then let it finish
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("fetch called %d times for concurrent callers, want 1", calls)
+	}
+}
+
+func TestCacheServesStaleDataOnError(t *testing.T) {
+	fail := false
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	c := NewCache(time.Minute, func(ctx context.Context) ([]PriceItem, error) {
+		if fail {
+			return nil, errors.New("api down")
+		}
+		return []PriceItem{{ItemID: 7, LowestSellPrice: 42}}, nil
+	})
+	c.now = clock.Now
+
+	if _, err := c.Get(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	fail = true
+	clock.Advance(time.Hour)
+	snap, err := c.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get with stale data returned error: %v", err)
+	}
+	if !snap.Stale || len(snap.Items) != 1 || snap.Items[0].LowestSellPrice != 42 {
+		t.Errorf("snap = %+v, want stale copy of the last good data", snap)
+	}
+}
+
+func TestCacheServesStaleDataWhileRefreshing(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	c := NewCache(time.Minute, func(ctx context.Context) ([]PriceItem, error) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			<-release
+		}
+		return []PriceItem{{ItemID: 7, LowestSellPrice: float64(atomic.LoadInt32(&calls))}}, nil
+	})
+	c.now = clock.Now
+	c.staleWait = 20 * time.Millisecond
+
+	if _, err := c.Get(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// a hanging API doesn't hold the caller past the stale wait
+	clock.Advance(time.Hour)
+	start := time.Now()
+	snap, err := c.Get(context.Background())
+	if err != nil || !snap.Stale || snap.Items[0].LowestSellPrice != 1 {
+		t.Fatalf("Get while refreshing = %+v, %v; want the stale copy", snap, err)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("Get waited %v for a hanging refresh", waited)
+	}
+
+	// the refresh finishes in the background and later callers get its data
+	close(release)
+	for i := 0; i < 100; i++ {
+		if snap, _ = c.Get(context.Background()); !snap.Stale {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if snap.Stale || snap.Items[0].LowestSellPrice != 2 || calls != 2 {
+		t.Errorf("after the background refresh: snap = %+v, %d fetches", snap, calls)
+	}
+}
+
+func TestCacheErrorWithoutData(t *testing.T) {
+	c := NewCache(time.Minute, func(ctx context.Context) ([]PriceItem, error) {
+		return nil, errors.New("api down")
+	})
+	if _, err := c.Get(context.Background()); err == nil {
+		t.Error("Get with no data and a failing API returned nil error")
+	}
+}
@@ -0,0 +1,302 @@
+package market
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// CategoryFood is the catalog category of items that heal.
+const CategoryFood = "food"
+
+//go:embed items.json
+var embeddedCatalog []byte
+
+var titleizer = cases.Title(language.Und)
+
+// Item describes a tradeable item.
+type Item struct {
+	ID       int            `json:"id"`
+	Name     string         `json:"name"` // game name_id, e.g. "cooked_tuna"
+	Category string         `json:"category"`
+	Healing  int            `json:"healing,omitempty"`
+	Stats    map[string]int `json:"stats,omitempty"`
+	Recipe   *Recipe        `json:"recipe,omitempty"`
+}
+
+// Recipe lists what goes into crafting one unit of an item.
+type Recipe struct {
+	Ingredients map[string]int `json:"ingredients"`       // ingredient name_id -> quantity
+	Seconds     float64        `json:"seconds,omitempty"` // crafting time, 0 when unknown
+}
+
+// DisplayName returns the human readable item name, e.g. "Cooked Tuna".
+func (it Item) DisplayName() string {
+	return titleizer.String(strings.ReplaceAll(it.Name, "_", " "))
+}
+
+// Catalog is an immutable, indexed list of items.
+type Catalog struct {
+	items  []Item
+	byID   map[int]Item
+	byName map[string]Item
+}
+
+// NewCatalog indexes the given items by ID and name.
+func NewCatalog(items []Item) *Catalog {
+	c := &Catalog{
+		items:  make([]Item, 0, len(items)),
+		byID:   make(map[int]Item, len(items)),
+		byName: make(map[string]Item, len(items)),
+	}
+	for _, it := range items {
+		it.Name = strings.ToLower(strings.TrimSpace(it.Name))
+		if it.Name == "" {
+			continue
+		}
+		c.items = append(c.items, it)
+		c.byID[it.ID] = it
+		c.byName[it.Name] = it
+	}
+	sort.Slice(c.items, func(i, j int) bool { return c.items[i].Name < c.items[j].Name })
+	return c
+}
+
+// ParseCatalog decodes a JSON array of items.
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	return NewCatalog(items), nil
+}
+
+// Items returns all items sorted by name.
+func (c *Catalog) Items() []Item {
+	return c.items
+}
+
+// ByID looks an item up by its internal ID.
+func (c *Catalog) ByID(id int) (Item, bool) {
+	it, ok := c.byID[id]
+	return it, ok
+}
+
+// DisplayName returns the display name of an item ID, or "Item #ID" when it is not in the catalog.
+func (c *Catalog) DisplayName(id int) string {
+	if it, ok := c.byID[id]; ok {
+		return it.DisplayName()
+	}
+	return fmt.Sprintf("Item #%d", id)
+}
+
+// Merge returns a catalog of c's items plus items, which replace entries with the same ID or name.
+func (c *Catalog) Merge(items []Item) *Catalog {
+	replaced := make(map[int]bool, len(items))
+	names := make(map[string]bool, len(items))
+	for _, it := range items {
+		replaced[it.ID] = true
+		names[normalizeName(it.Name)] = true
+	}
+	merged := make([]Item, 0, len(c.items)+len(items))
+	for _, it := range c.items {
+		if !replaced[it.ID] && !names[it.Name] {
+			merged = append(merged, it)
+		}
+	}
+	return NewCatalog(append(merged, items...))
+}
+
+// Categories returns the distinct item categories, sorted.
+func (c *Catalog) Categories() []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, it := range c.items {
+		cat := strings.ToLower(it.Category)
+		if cat != "" && !seen[cat] {
+			seen[cat] = true
+			result = append(result, cat)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ByName looks an item up by name_id or display name, case-insensitively.
+func (c *Catalog) ByName(name string) (Item, bool) {
+	it, ok := c.byName[normalizeName(name)]
+	return it, ok
+}
+
+// InCategory returns every item of the given category.
+func (c *Catalog) InCategory(category string) []Item {
+	var result []Item
+	for _, it := range c.items {
+		if strings.EqualFold(it.Category, category) {
+			result = append(result, it)
+		}
+	}
+	return result
+}
+
+// Craftable returns every item that has a recipe.
+func (c *Catalog) Craftable() []Item {
+	var result []Item
+	for _, it := range c.items {
+		if it.Recipe != nil && len(it.Recipe.Ingredients) > 0 {
+			result = append(result, it)
+		}
+	}
+	return result
+}
+
+// Search returns up to limit items whose name fuzzily matches query, best matches first.
+// An empty query matches every item.
+func (c *Catalog) Search(query string, limit int) []Item {
+	q := normalizeName(query)
+
+	type scored struct {
+		item  Item
+		score int
+	}
+	var matches []scored
+	for _, it := range c.items {
+		if s := matchScore(it.Name, q); s > 0 {
+			matches = append(matches, scored{it, s})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	result := make([]Item, len(matches))
+	for i, m := range matches {
+		result[i] = m.item
+	}
+	return result
+}
+
+// matchScore ranks how well name matches the normalized query; 0 means no match.
+// Exact > prefix > word prefix > substring > in-order subsequence.
+func matchScore(name, q string) int {
+	switch {
+	case q == "":
+		return 1
+	case name == q:
+		return 100
+	case strings.HasPrefix(name, q):
+		return 80
+	case strings.Contains(name, "_"+q):
+		return 60
+	case strings.Contains(name, q):
+		return 40
+	case isSubsequence(strings.ReplaceAll(q, "_", ""), name):
+		return 10
+	}
+	return 0
+}
+
+// isSubsequence reports whether every rune of q appears in s in order.
+func isSubsequence(q, s string) bool {
+	if q == "" {
+		return false
+	}
+	qr := []rune(q)
+	i := 0
+	for _, r := range s {
+		if r == qr[i] {
+			i++
+			if i == len(qr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeName turns "Cooked Tuna" or "cooked_tuna" into the name_id form.
+func normalizeName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+}
+
+var (
+	catalogMu     sync.RWMutex
+	activeCatalog *Catalog
+)
+
+// DefaultCatalog returns the active catalog: the embedded one unless LoadCatalog replaced it.
+func DefaultCatalog() *Catalog {
+	catalogMu.RLock()
+	c := activeCatalog
+	catalogMu.RUnlock()
+	if c != nil {
+		return c
+	}
+
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if activeCatalog == nil {
+		activeCatalog = parseEmbeddedCatalog()
+	}
+	return activeCatalog
+}
+
+func parseEmbeddedCatalog() *Catalog {
+	c, err := ParseCatalog(embeddedCatalog)
+	if err != nil {
+		// the embedded file is part of the build; a parse error is a programming mistake
+		panic(fmt.Sprintf("market: invalid embedded item catalog: %v", err))
+	}
+	return c
+}
+
+// LoadCatalog fetches a JSON item catalog from url and merges it over the embedded one to
+// make the default catalog, so a served catalog may add or correct just some items, such as
+// the ingredients of the embedded recipes. On failure the previously active (or embedded)
+// catalog stays in place.
+func LoadCatalog(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("catalog API returned status %d", resp.StatusCode)
+	}
+
+	c, err := ParseCatalog(body)
+	if err != nil {
+		return err
+	}
+	if len(c.Items()) == 0 {
+		return fmt.Errorf("catalog at %s is empty", url)
+	}
+
+	merged := parseEmbeddedCatalog().Merge(c.Items())
+	catalogMu.Lock()
+	activeCatalog = merged
+	catalogMu.Unlock()
+	return nil
+}
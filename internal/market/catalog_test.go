@@ -0,0 +1,80 @@
+package market
+
+import "testing"
+
+func TestEmbeddedCatalogFoods(t *testing.T) {
+	foods := DefaultCatalog().InCategory(CategoryFood)
+	if len(foods) != 25 {
+		t.Fatalf("len(foods) = %d, want 25", len(foods))
+	}
+	for _, f := range foods {
+		if f.ID <= 0 || f.Healing <= 0 {
+			t.Errorf("food %+v missing ID or healing value", f)
+		}
+	}
+
+	tuna, ok := DefaultCatalog().ByName("Cooked Tuna")
+	if !ok || tuna.ID != 562 || tuna.Healing != 17 {
+		t.Errorf("ByName(Cooked Tuna) = %+v, %v", tuna, ok)
+	}
+}
+
+func TestCatalogMerge(t *testing.T) {
+	c := NewCatalog([]Item{
+		{ID: 1, Name: "cooked_tuna", Category: CategoryFood, Healing: 17},
+		{ID: 2, Name: "old_name"},
+	}).Merge([]Item{
+		{ID: 2, Name: "new_name"},
+		{ID: 3, Name: "raw_tuna"},
+	})
+
+	if len(c.Items()) != 3 {
+		t.Errorf("merged items = %+v, want 3", c.Items())
+	}
+	if _, ok := c.ByName("old_name"); ok {
+		t.Error("replaced item is still found by its old name")
+	}
+	if it, ok := c.ByID(2); !ok || it.Name != "new_name" {
+		t.Errorf("ByID(2) = %+v, %v", it, ok)
+	}
+	if it, ok := c.ByName("cooked_tuna"); !ok || it.Healing != 17 {
+		t.Errorf("ByName(cooked_tuna) = %+v, %v", it, ok)
+	}
+}
+
+func TestCatalogSearch(t *testing.T) {
+	c := NewCatalog([]Item{
+		{ID: 1, Name: "cooked_salmon"},
+		{ID: 2, Name: "salmon_salad"},
+		{ID: 3, Name: "cooked_meat"},
+		{ID: 4, Name: "stew"},
+	})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"salmon", []string{"salmon_salad", "cooked_salmon"}},
+		{"Cooked S", []string{"cooked_salmon"}},
+		{"ckdmt", []string{"cooked_meat"}},
+		{"pizza", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := c.Search(tt.query, 25)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Search(%q) returned %d items, want %d: %+v", tt.query, len(got), len(tt.want), got)
+			}
+			for i, it := range got {
+				if it.Name != tt.want[i] {
+					t.Errorf("Search(%q)[%d] = %s, want %s", tt.query, i, it.Name, tt.want[i])
+				}
+			}
+		})
+	}
+
+	if got := c.Search("", 2); len(got) != 2 {
+		t.Errorf("Search(\"\", 2) returned %d items, want 2", len(got))
+	}
+}
@@ -0,0 +1,91 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"klutco-lil-helper/internal/metrics"
+)
+
+// LatestPricesURL is the Idle Clans endpoint returning the current price of every traded item.
+const LatestPricesURL = "https://query.idleclans.com/api/PlayerMarket/items/prices/latest?includeAveragePrice=true"
+
+// httpClient is shared by every request to the Idle Clans API.
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// PriceItem represents the market price data of a single item.
+type PriceItem struct {
+	ItemID          int     `json:"itemId"`
+	LowestSellPrice float64 `json:"lowestSellPrice"`
+	AveragePrice    float64 `json:"averagePrice"`
+}
+
+// FetchLatestPrices fetches the latest market prices from the API.
+func FetchLatestPrices(ctx context.Context) ([]PriceItem, error) {
+	var marketData []PriceItem
+	var lastErr error
+
+	// Retry logic: 3 attempts with exponential backoff
+	for attempt := 0; attempt < 3; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", LatestPricesURL, nil)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		start := time.Now()
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			metrics.MarketAPILatency.ObserveDuration("error", start)
+			lastErr = err
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			metrics.MarketAPILatency.ObserveDuration("error", start)
+			lastErr = fmt.Errorf("API returned status %d", resp.StatusCode)
+			continue
+		}
+
+		metrics.MarketAPILatency.ObserveDuration("ok", start)
+
+		err = json.Unmarshal(body, &marketData)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return marketData, nil
+	}
+
+	return nil, fmt.Errorf("failed after 3 attempts: %w", lastErr)
+}
+
+// LowestSellPrices indexes the lowest sell price of each item by item ID.
+func LowestSellPrices(items []PriceItem) map[int]float64 {
+	priceMap := make(map[int]float64, len(items))
+	for _, item := range items {
+		priceMap[item.ItemID] = item.LowestSellPrice
+	}
+	return priceMap
+}
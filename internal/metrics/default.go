@@ -0,0 +1,32 @@
+package metrics
+
+// Default is the registry served on /metrics.
+var Default = NewRegistry()
+
+// Metrics recorded across the bot.
+var (
+	ClanMessagesFetched = Default.NewCounter("lilhelper_clan_messages_fetched_total",
+		"Clan log lines returned by the Idle Clans API.")
+	ClanMessagesInserted = Default.NewCounter("lilhelper_clan_messages_inserted_total",
+		"Clan log lines stored for the first time.")
+	ClanLogFetchErrors = Default.NewCounter("lilhelper_clan_log_fetch_errors_total",
+		"Clan log fetches that failed after all retries.")
+	ClanLogLastSuccess = Default.NewGauge("lilhelper_clan_log_last_success_timestamp_seconds",
+		"Unix time of the last successful clan log fetch.")
+	ClanMessagesRelayed = Default.NewCounter("lilhelper_clan_messages_relayed_total",
+		"Clan log lines posted to Discord.")
+	ClanMessagesDropped = Default.NewCounter("lilhelper_clan_messages_dropped_total",
+		"Clan log lines not relayed because a routing rule drops their category.")
+	ClanMessagesDeadLettered = Default.NewCounter("lilhelper_clan_messages_dead_lettered_total",
+		"Clan log lines given up on after too many failed sends.")
+	ClanMessagesArchived = Default.NewCounter("lilhelper_clan_messages_archived_total",
+		"Sent clan log lines moved into the compressed archive by the retention policy.")
+	DiscordAPIErrors = Default.NewCounterVec("lilhelper_discord_api_errors_total",
+		"Discord REST calls that failed, by HTTP status or \"transport\".", "status")
+	JobDuration = Default.NewHistogramVec("lilhelper_job_duration_seconds",
+		"Duration of background job runs.", "job", nil)
+	WorkerRestarts = Default.NewCounterVec("lilhelper_worker_restarts_total",
+		"Background workers restarted after crashing or exiting early.", "worker")
+	MarketAPILatency = Default.NewHistogramVec("lilhelper_market_api_request_duration_seconds",
+		"Latency of Idle Clans market API requests, by result.", "result", nil)
+)
@@ -0,0 +1,253 @@
+// Package metrics implements the few Prometheus metric types the bot needs
+// and renders them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBuckets are histogram upper bounds in seconds, suited to API calls and background jobs.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// collector is a metric that can write itself in the text format.
+type collector interface {
+	write(w io.Writer)
+}
+
+// Registry holds metrics in registration order.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	r.collectors = append(r.collectors, c)
+	r.mu.Unlock()
+}
+
+// Write renders every registered metric.
+func (r *Registry) Write(w io.Writer) {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// Handler serves the registry for Prometheus to scrape.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Write(w)
+	})
+}
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	name, help string
+	v          atomic.Uint64
+}
+
+// NewCounter registers a counter.
+func (r *Registry) NewCounter(name, help string) *Counter {
+	c := &Counter{name: name, help: help}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the counter.
+func (c *Counter) Inc() { c.v.Add(1) }
+
+// Add adds n to the counter.
+func (c *Counter) Add(n int) {
+	if n > 0 {
+		c.v.Add(uint64(n))
+	}
+}
+
+// Value returns the current count.
+func (c *Counter) Value() uint64 { return c.v.Load() }
+
+func (c *Counter) write(w io.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	fmt.Fprintf(w, "%s %d\n", c.name, c.v.Load())
+}
+
+// CounterVec is a set of counters partitioned by one label.
+type CounterVec struct {
+	name, help, label string
+	mu                sync.Mutex
+	values            map[string]*atomic.Uint64
+}
+
+// NewCounterVec registers a counter with a single label.
+func (r *Registry) NewCounterVec(name, help, label string) *CounterVec {
+	c := &CounterVec{name: name, help: help, label: label, values: map[string]*atomic.Uint64{}}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the counter for the label value.
+func (c *CounterVec) Inc(value string) {
+	c.mu.Lock()
+	v, ok := c.values[value]
+	if !ok {
+		v = &atomic.Uint64{}
+		c.values[value] = v
+	}
+	c.mu.Unlock()
+	v.Add(1)
+}
+
+// Value returns the count for the label value.
+func (c *CounterVec) Value(value string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.values[value]; ok {
+		return v.Load()
+	}
+	return 0
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, value := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s{%s=%q} %d\n", c.name, c.label, value, c.values[value].Load())
+	}
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	name, help string
+	bits       atomic.Uint64
+}
+
+// NewGauge registers a gauge.
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	g := &Gauge{name: name, help: help}
+	r.register(g)
+	return g
+}
+
+// Set replaces the gauge value.
+func (g *Gauge) Set(v float64) { g.bits.Store(math.Float64bits(v)) }
+
+// Value returns the current value.
+func (g *Gauge) Value() float64 { return math.Float64frombits(g.bits.Load()) }
+
+func (g *Gauge) write(w io.Writer) {
+	writeHeader(w, g.name, g.help, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.Value()))
+}
+
+// HistogramVec is a set of histograms partitioned by one label.
+type HistogramVec struct {
+	name, help, label string
+	buckets           []float64
+	mu                sync.Mutex
+	values            map[string]*histogram
+}
+
+// histogram counts observations per bucket; counts are not cumulative until written.
+type histogram struct {
+	counts []uint64 // one per bucket, plus +Inf
+	sum    float64
+	count  uint64
+}
+
+// NewHistogramVec registers a histogram with a single label. Nil buckets use DefaultBuckets.
+func (r *Registry) NewHistogramVec(name, help, label string, buckets []float64) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	h := &HistogramVec{name: name, help: help, label: label, buckets: buckets, values: map[string]*histogram{}}
+	r.register(h)
+	return h
+}
+
+// Observe records a value for the label value.
+func (h *HistogramVec) Observe(value string, v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hist, ok := h.values[value]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets)+1)}
+		h.values[value] = hist
+	}
+	idx := sort.SearchFloat64s(h.buckets, v)
+	hist.counts[idx]++
+	hist.sum += v
+	hist.count++
+}
+
+// ObserveDuration records the time elapsed since start, in seconds.
+func (h *HistogramVec) ObserveDuration(value string, start time.Time) {
+	h.Observe(value, time.Since(start).Seconds())
+}
+
+// Count returns how many values were observed for the label value.
+func (h *HistogramVec) Count(value string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if hist, ok := h.values[value]; ok {
+		return hist.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, value := range sortedKeys(h.values) {
+		hist := h.values[value]
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += hist.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s=%q,le=%q} %d\n", h.name, h.label, value, formatFloat(le), cumulative)
+		}
+		cumulative += hist.counts[len(h.buckets)]
+		fmt.Fprintf(w, "%s_bucket{%s=%q,le=\"+Inf\"} %d\n", h.name, h.label, value, cumulative)
+		fmt.Fprintf(w, "%s_sum{%s=%q} %s\n", h.name, h.label, value, formatFloat(hist.sum))
+		fmt.Fprintf(w, "%s_count{%s=%q} %d\n", h.name, h.label, value, hist.count)
+	}
+}
+
+func writeHeader(w io.Writer, name, help, kind string) {
+	help = strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
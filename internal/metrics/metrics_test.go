@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryTextFormat(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_total", "A test counter.")
+	v := r.NewCounterVec("test_errors_total", "Errors by status.", "status")
+	g := r.NewGauge("test_gauge", "A test gauge.")
+	h := r.NewHistogramVec("test_seconds", "A test histogram.", "job", []float64{1, 5})
+
+	c.Add(3)
+	v.Inc("500")
+	v.Inc("429")
+	v.Inc("500")
+	g.Set(1.5)
+	h.Observe("fetch", 0.5)
+	h.Observe("fetch", 2)
+	h.Observe("fetch", 10)
+
+	var sb strings.Builder
+	r.Write(&sb)
+
+	want := `# HELP test_total A test counter.
+# TYPE test_total counter
+test_total 3
+# HELP test_errors_total Errors by status.
+# TYPE test_errors_total counter
+test_errors_total{status="429"} 1
+test_errors_total{status="500"} 2
+# HELP test_gauge A test gauge.
+# TYPE test_gauge gauge
+test_gauge 1.5
+# HELP test_seconds A test histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{job="fetch",le="1"} 1
+test_seconds_bucket{job="fetch",le="5"} 2
+test_seconds_bucket{job="fetch",le="+Inf"} 3
+test_seconds_sum{job="fetch"} 12.5
+test_seconds_count{job="fetch"} 3
+`
+	if got := sb.String(); got != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHistogramBucketBoundaryIsInclusive(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("b_seconds", "Boundary.", "job", []float64{1})
+	h.Observe("x", 1)
+
+	var sb strings.Builder
+	r.Write(&sb)
+	if !strings.Contains(sb.String(), `b_seconds_bucket{job="x",le="1"} 1`) {
+		t.Errorf("value equal to a bucket bound not counted in it:\n%s", sb.String())
+	}
+}
+
+func TestHandlerContentType(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("x_total", "X.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "x_total 1") {
+		t.Errorf("body missing counter:\n%s", rec.Body.String())
+	}
+}
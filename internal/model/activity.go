@@ -0,0 +1,77 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const createPollResponsesTableQuery = `
+CREATE TABLE IF NOT EXISTS poll_responses (
+    discord_id TEXT PRIMARY KEY,
+    last_response_at TEXT NOT NULL
+);
+`
+
+// CreatePollResponsesTable creates the poll_responses table if it does not exist.
+func CreatePollResponsesTable(db *sql.DB) error {
+	_, err := db.Exec(createPollResponsesTableQuery)
+	return err
+}
+
+// RecordPollResponse stores that a Discord user reacted to a boss poll, keeping the latest time.
+func RecordPollResponse(db *sql.DB, discordID string, at time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO poll_responses (discord_id, last_response_at) VALUES (?, ?)
+		ON CONFLICT (discord_id) DO UPDATE SET
+			last_response_at = MAX(last_response_at, excluded.last_response_at)
+	`, discordID, at.UTC().Format(time.RFC3339))
+	return err
+}
+
+// IsBossPollMessage reports whether messageID is the current daily or weekly boss poll.
+func IsBossPollMessage(db *sql.DB, messageID string) (bool, error) {
+	var n int
+	err := db.QueryRow(`
+		SELECT COUNT(*) FROM scheduled_messages
+		WHERE message_id = ? AND type IN (?, ?)
+	`, messageID, MessageTypeDaily, MessageTypeWeekly).Scan(&n)
+	return n > 0, err
+}
+
+// LastPollResponses returns when each Discord user last reacted to a boss poll.
+func LastPollResponses(db *sql.DB) (map[string]time.Time, error) {
+	return queryLastSeen(db, `SELECT discord_id, last_response_at FROM poll_responses`)
+}
+
+// LastClanLogActivity returns the time of each member's latest clan log line, archived ones included.
+func LastClanLogActivity(db *sql.DB) (map[string]time.Time, error) {
+	return queryLastSeen(db, `
+		SELECT member_username, MAX(last_seen) FROM (
+			SELECT member_username, MAX(timestamp) AS last_seen FROM clan_messages GROUP BY member_username
+			UNION ALL
+			SELECT member_username, last_seen FROM clan_member_last_seen
+		)
+		GROUP BY member_username
+	`)
+}
+
+func queryLastSeen(db *sql.DB, query string) (map[string]time.Time, error) {
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seen := make(map[string]time.Time)
+	for rows.Next() {
+		var key string
+		var at sql.NullString
+		if err := rows.Scan(&key, &at); err != nil {
+			return nil, err
+		}
+		if t := parseStoredTime(at); !t.IsZero() {
+			seen[key] = t
+		}
+	}
+	return seen, rows.Err()
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPollResponsesAndClanLogActivity(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := UpsertScheduledMessage(db, MessageTypeDaily, "boss", "poll-1"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := IsBossPollMessage(db, "poll-1"); err != nil || !ok {
+		t.Errorf("IsBossPollMessage(poll-1) = %v, %v; want true", ok, err)
+	}
+	if ok, _ := IsBossPollMessage(db, "something-else"); ok {
+		t.Error("unrelated message counted as a poll")
+	}
+
+	if err := RecordPollResponse(db, "u1", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordPollResponse(db, "u1", now.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	polls, err := LastPollResponses(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !polls["u1"].Equal(now) {
+		t.Errorf("u1 last responded %s, want %s", polls["u1"], now)
+	}
+
+	insertTestMessage(t, db, "tester joined the clan.", now.Add(-48*time.Hour))
+	insertTestMessage(t, db, "tester added 5x Gold.", now.Add(-24*time.Hour))
+	logs, err := LastClanLogActivity(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !logs["tester"].Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("tester last logged %s, want a day ago", logs["tester"])
+	}
+}
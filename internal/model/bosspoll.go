@@ -0,0 +1,71 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// BossParticipation counts the boss poll reactions of one boss over a period.
+type BossParticipation struct {
+	SignUps int // reactions across every poll
+	Members int // distinct members who reacted
+}
+
+const createBossPollReactionsTableQuery = `
+CREATE TABLE IF NOT EXISTS boss_poll_reactions (
+    message_id TEXT NOT NULL,
+    emoji TEXT NOT NULL,
+    discord_id TEXT NOT NULL,
+    reacted_at TEXT NOT NULL,
+    PRIMARY KEY (message_id, emoji, discord_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_boss_poll_reactions_reacted_at ON boss_poll_reactions (reacted_at);
+`
+
+// CreateBossPollReactionsTable creates the boss_poll_reactions table if it does not exist.
+func CreateBossPollReactionsTable(db *sql.DB) error {
+	_, err := db.Exec(createBossPollReactionsTableQuery)
+	return err
+}
+
+// RecordBossPollReaction stores a reaction to a boss poll, keeping the time it was first added.
+func RecordBossPollReaction(db *sql.DB, messageID, emoji, discordID string, at time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO boss_poll_reactions (message_id, emoji, discord_id, reacted_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (message_id, emoji, discord_id) DO NOTHING
+	`, messageID, emoji, discordID, at.UTC().Format(time.RFC3339))
+	return err
+}
+
+// DeleteBossPollReaction forgets a reaction that was taken back.
+func DeleteBossPollReaction(db *sql.DB, messageID, emoji, discordID string) error {
+	_, err := db.Exec(`DELETE FROM boss_poll_reactions WHERE message_id = ? AND emoji = ? AND discord_id = ?`,
+		messageID, emoji, discordID)
+	return err
+}
+
+// BossPollParticipation counts the reactions added in [since, until), keyed by emoji.
+func BossPollParticipation(db *sql.DB, since, until time.Time) (map[string]BossParticipation, error) {
+	rows, err := db.Query(`
+		SELECT emoji, COUNT(*), COUNT(DISTINCT discord_id)
+		FROM boss_poll_reactions
+		WHERE reacted_at >= ? AND reacted_at < ?
+		GROUP BY emoji
+	`, since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]BossParticipation)
+	for rows.Next() {
+		var emoji string
+		var p BossParticipation
+		if err := rows.Scan(&emoji, &p.SignUps, &p.Members); err != nil {
+			return nil, err
+		}
+		counts[emoji] = p
+	}
+	return counts, rows.Err()
+}
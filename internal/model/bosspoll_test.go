@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBossPollParticipation(t *testing.T) {
+	db := newTestDB(t)
+	monday := time.Date(2026, 3, 2, 5, 0, 0, 0, time.UTC)
+
+	reactions := []struct {
+		msg, emoji, user string
+		at               time.Time
+	}{
+		{"d1", "🐔", "a", monday.Add(time.Hour)},
+		{"d1", "🐔", "b", monday.Add(time.Hour)},
+		{"d2", "🐔", "a", monday.AddDate(0, 0, 1)},
+		{"d1", "😈", "a", monday.Add(time.Hour)},
+		{"d1", "😈", "c", monday.Add(time.Hour)},
+		{"old", "🐔", "c", monday.Add(-time.Hour)},
+		{"next", "🐔", "c", monday.AddDate(0, 0, 7)},
+	}
+	for _, r := range reactions {
+		if err := RecordBossPollReaction(db, r.msg, r.emoji, r.user, r.at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// reacting again keeps one row; a removed reaction no longer counts
+	if err := RecordBossPollReaction(db, "d1", "🐔", "a", monday.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteBossPollReaction(db, "d1", "😈", "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	counts, err := BossPollParticipation(db, monday, monday.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := counts["🐔"]; got != (BossParticipation{SignUps: 3, Members: 2}) {
+		t.Errorf("griffin = %+v", got)
+	}
+	if got := counts["😈"]; got != (BossParticipation{SignUps: 1, Members: 1}) {
+		t.Errorf("hades = %+v", got)
+	}
+}
@@ -13,6 +13,12 @@ type ClanMessage struct {
 	Message        string    `json:"message"`
 	Timestamp      time.Time `json:"timestamp"`
 	MessageSent    bool      `json:"messageSent"`
+
+	// delivery bookkeeping, see clanmessage_delivery.go
+	State         DeliveryState `json:"state"`
+	Attempts      int           `json:"attempts"`
+	LastError     string        `json:"lastError,omitempty"`
+	NextAttemptAt time.Time     `json:"nextAttemptAt,omitempty"`
 }
 
 const createTableQuery = `
@@ -29,31 +35,54 @@ CREATE TABLE IF NOT EXISTS clan_messages (
 CREATE UNIQUE INDEX IF NOT EXISTS idx_clan_messages_unique ON clan_messages (clan_name, member_username, message, timestamp);
 `
 
-func InsertClanMessage(db *sql.DB, msg ClanMessage) error {
+// InsertClanMessage stores a clan message, ignoring duplicates and lines older than the
+// archive cutoff, which were already stored once (see ArchiveClanMessages).
+// It reports whether a new row was actually inserted.
+func InsertClanMessage(db *sql.DB, msg ClanMessage) (bool, error) {
 	query := `
         INSERT OR IGNORE INTO clan_messages (clan_name, member_username, message, timestamp)
-        VALUES (?, ?, ?, ?)
+        SELECT ?, ?, ?, ?
+        WHERE ? >= COALESCE((SELECT MAX(archived_before) FROM clan_messages_archive), '')
     `
-	_, err := db.Exec(query,
+	ts := msg.Timestamp.UTC().Format(time.RFC3339)
+	res, err := db.Exec(query,
 		msg.ClanName,
 		msg.MemberUsername,
 		msg.Message,
-		msg.Timestamp.UTC().Format(time.RFC3339),
+		ts,
+		ts,
 	)
-	return err
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
 }
 
-func GetMessages(db *sql.DB) ([]ClanMessage, error) {
-	// return up to 10 oldest unsent messages
-	query := `
-        SELECT id, clan_name, member_username, message, timestamp, message_sent
+// getMessagesQuery selects the oldest pending lines that are due; idx_clan_messages_delivery
+// serves both the filter and the order.
+const getMessagesQuery = `
+        SELECT ` + clanMessageColumns + `
         FROM clan_messages
-        WHERE message_sent = 0
-        ORDER BY timestamp ASC
-        LIMIT 10
+        WHERE message_sent = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)
+        ORDER BY timestamp ASC, id ASC
+        LIMIT ?
     `
 
-	rows, err := db.Query(query)
+// GetMessages returns up to limit oldest pending messages whose next delivery attempt is due at now.
+func GetMessages(db *sql.DB, now time.Time, limit int) ([]ClanMessage, error) {
+	return queryClanMessages(db, getMessagesQuery, DeliveryPending, now.UTC().Format(time.RFC3339), limit)
+}
+
+// clanMessageColumns is the column list read by scanClanMessage.
+const clanMessageColumns = `id, clan_name, member_username, message, timestamp, message_sent, attempts, last_error, next_attempt_at`
+
+// queryClanMessages runs a query selecting clanMessageColumns.
+func queryClanMessages(db *sql.DB, query string, args ...interface{}) ([]ClanMessage, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +94,7 @@ func GetMessages(db *sql.DB) ([]ClanMessage, error) {
 		var msg ClanMessage
 		var ts string
 		var sentInt int
+		var nextAttempt sql.NullString
 
 		if err := rows.Scan(
 			&msg.ID,
@@ -73,6 +103,9 @@ func GetMessages(db *sql.DB) ([]ClanMessage, error) {
 			&msg.Message,
 			&ts,
 			&sentInt,
+			&msg.Attempts,
+			&msg.LastError,
+			&nextAttempt,
 		); err != nil {
 			return nil, err
 		}
@@ -90,22 +123,26 @@ func GetMessages(db *sql.DB) ([]ClanMessage, error) {
 			}
 		}
 
-		msg.MessageSent = sentInt != 0
+		msg.State = DeliveryState(sentInt)
+		msg.MessageSent = msg.State == DeliverySent
+		if nextAttempt.Valid {
+			msg.NextAttemptAt, _ = time.Parse(time.RFC3339, nextAttempt.String)
+		}
 
 		results = append(results, msg)
 	}
 
-	return results, nil
+	return results, rows.Err()
 }
 
-// MarkMessagesSent marks the provided message IDs as sent (message_sent = 1).
+// MarkMessagesSent marks the provided message IDs as sent (message_sent = DeliverySent).
 func MarkMessagesSent(db *sql.DB, ids []int64) error {
 	if len(ids) == 0 {
 		return nil
 	}
 
 	placeholders := strings.TrimRight(strings.Repeat("?,", len(ids)), ",")
-	query := "UPDATE clan_messages SET message_sent = 1 WHERE id IN (" + placeholders + ")"
+	query := "UPDATE clan_messages SET message_sent = 1, next_attempt_at = NULL WHERE id IN (" + placeholders + ")"
 
 	args := make([]interface{}, 0, len(ids))
 	for _, id := range ids {
@@ -153,6 +190,8 @@ func MigrateClanMessages(db *sql.DB) error {
 			break
 		}
 	}
+	// release the connection before issuing more statements
+	_ = rows.Close()
 
 	if hasID {
 		// ensure unique index exists
@@ -0,0 +1,301 @@
+package model
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// archiveDay is the layout of the UTC day columns of the archive and event count tables.
+	archiveDay = "2006-01-02"
+	// maxArchiveBatch keeps the DELETE of a batch well below SQLite's bound parameter limit.
+	maxArchiveBatch = 5000
+)
+
+// clanMessageIndexesQuery adds the indexes behind the hot clan_messages queries: the relay's
+// pending scan and the dead-letter listing (message_sent, ordered by timestamp), time range
+// summaries and retention (timestamp), and last activity per member (member_username).
+const clanMessageIndexesQuery = `
+CREATE INDEX IF NOT EXISTS idx_clan_messages_delivery ON clan_messages (message_sent, timestamp, id);
+CREATE INDEX IF NOT EXISTS idx_clan_messages_timestamp ON clan_messages (timestamp);
+CREATE INDEX IF NOT EXISTS idx_clan_messages_member ON clan_messages (member_username, timestamp);
+`
+
+const createClanMessageArchiveTablesQuery = `
+CREATE TABLE IF NOT EXISTS clan_messages_archive (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    day TEXT NOT NULL,
+    archived_before TEXT NOT NULL,
+    message_count INTEGER NOT NULL,
+    data BLOB NOT NULL,
+    archived_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_clan_messages_archive_day ON clan_messages_archive (day);
+CREATE INDEX IF NOT EXISTS idx_clan_messages_archive_before ON clan_messages_archive (archived_before);
+
+CREATE TABLE IF NOT EXISTS clan_event_counts (
+    day TEXT NOT NULL,
+    category TEXT NOT NULL,
+    count INTEGER NOT NULL,
+    PRIMARY KEY (day, category)
+);
+
+CREATE TABLE IF NOT EXISTS clan_member_last_seen (
+    member_username TEXT PRIMARY KEY,
+    last_seen TEXT NOT NULL
+);
+`
+
+// MigrateClanMessageRetention adds the clan_messages indexes and creates the archive, daily
+// event count and member last seen tables.
+func MigrateClanMessageRetention(db *sql.DB) error {
+	if _, err := db.Exec(clanMessageIndexesQuery); err != nil {
+		return err
+	}
+	_, err := db.Exec(createClanMessageArchiveTablesQuery)
+	return err
+}
+
+// ArchiveResult is what one ArchiveClanMessages run moved out of clan_messages.
+type ArchiveResult struct {
+	Messages int64 // lines archived
+	Batches  int   // archive rows written, one per UTC day of each batch
+}
+
+// ArchiveClanMessages moves sent clan log lines older than before into clan_messages_archive,
+// gzip-compressed as JSON lines per UTC day, and adds them to the daily event counts. Each
+// member's latest archived line is kept in clan_member_last_seen for LastClanLogActivity.
+// Pending and dead-lettered lines are never archived. Work is split into transactions of at
+// most batchSize lines so the relay isn't blocked for long.
+//
+// The archive cutoff doubles as a watermark: InsertClanMessage ignores lines older than it,
+// so the clan log fetcher doesn't store and relay archived lines a second time.
+func ArchiveClanMessages(db *sql.DB, before time.Time, batchSize int) (ArchiveResult, error) {
+	var result ArchiveResult
+	if batchSize <= 0 || batchSize > maxArchiveBatch {
+		batchSize = maxArchiveBatch
+	}
+	cutoff := before.UTC().Format(time.RFC3339)
+
+	for {
+		msgs, err := queryClanMessages(db, `
+			SELECT `+clanMessageColumns+`
+			FROM clan_messages
+			WHERE message_sent = ? AND timestamp < ?
+			ORDER BY timestamp ASC, id ASC
+			LIMIT ?
+		`, DeliverySent, cutoff, batchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(msgs) == 0 {
+			return result, nil
+		}
+
+		batches, err := archiveBatch(db, msgs, cutoff)
+		if err != nil {
+			return result, err
+		}
+		result.Messages += int64(len(msgs))
+		result.Batches += batches
+
+		if len(msgs) < batchSize {
+			return result, nil
+		}
+	}
+}
+
+// archiveBatch archives and deletes msgs in one transaction, returning the archive rows written.
+func archiveBatch(db *sql.DB, msgs []ClanMessage, cutoff string) (int, error) {
+	byDay := make(map[string][]ClanMessage)
+	for _, m := range msgs {
+		day := m.Timestamp.UTC().Format(archiveDay)
+		byDay[day] = append(byDay[day], m)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for day, dayMsgs := range byDay {
+		data, err := compressClanMessages(dayMsgs)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO clan_messages_archive (day, archived_before, message_count, data, archived_at)
+			VALUES (?, ?, ?, ?, ?)
+		`, day, cutoff, len(dayMsgs), data, now); err != nil {
+			return 0, err
+		}
+
+		counts := make(map[EventCategory]int)
+		for _, m := range dayMsgs {
+			counts[ClassifyClanMessage(m.Message)]++
+		}
+		for category, n := range counts {
+			if _, err := tx.Exec(`
+				INSERT INTO clan_event_counts (day, category, count) VALUES (?, ?, ?)
+				ON CONFLICT (day, category) DO UPDATE SET count = count + excluded.count
+			`, day, category, n); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	lastSeen := make(map[string]time.Time)
+	for _, m := range msgs {
+		if m.Timestamp.After(lastSeen[m.MemberUsername]) {
+			lastSeen[m.MemberUsername] = m.Timestamp
+		}
+	}
+	for member, at := range lastSeen {
+		if _, err := tx.Exec(`
+			INSERT INTO clan_member_last_seen (member_username, last_seen) VALUES (?, ?)
+			ON CONFLICT (member_username) DO UPDATE SET last_seen = MAX(last_seen, excluded.last_seen)
+		`, member, at.UTC().Format(time.RFC3339)); err != nil {
+			return 0, err
+		}
+	}
+
+	ids := make([]interface{}, len(msgs))
+	for i, m := range msgs {
+		ids[i] = m.ID
+	}
+	placeholders := strings.TrimRight(strings.Repeat("?,", len(ids)), ",")
+	if _, err := tx.Exec(`DELETE FROM clan_messages WHERE id IN (`+placeholders+`)`, ids...); err != nil {
+		return 0, err
+	}
+	return len(byDay), tx.Commit()
+}
+
+// compressClanMessages encodes messages as gzip-compressed JSON lines.
+func compressClanMessages(msgs []ClanMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for _, m := range msgs {
+		if err := enc.Encode(m); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadClanMessageArchive returns the archived lines of a UTC day, oldest first.
+func ReadClanMessageArchive(db *sql.DB, day time.Time) ([]ClanMessage, error) {
+	rows, err := db.Query(`SELECT data FROM clan_messages_archive WHERE day = ? ORDER BY id`, day.UTC().Format(archiveDay))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var msgs []ClanMessage
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(zr)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var m ClanMessage
+			if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, m)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Timestamp.Before(msgs[j].Timestamp) })
+	return msgs, nil
+}
+
+// DailyEventCount is how many clan log lines of one category were logged on a UTC day.
+type DailyEventCount struct {
+	Day      time.Time
+	Category EventCategory
+	Count    int
+}
+
+// DailyEventCounts counts the clan log lines of each UTC day in [since, until) by category,
+// combining the archived counts with the lines still in clan_messages. Days are oldest
+// first and categories follow EventCategories.
+func DailyEventCounts(db *sql.DB, since, until time.Time) ([]DailyEventCount, error) {
+	type key struct {
+		day      string
+		category EventCategory
+	}
+	counts := make(map[key]int)
+
+	rows, err := db.Query(`SELECT day, category, count FROM clan_event_counts WHERE day >= ? AND day < ?`,
+		since.UTC().Format(archiveDay), until.UTC().Format(archiveDay))
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var k key
+		var n int
+		if err := rows.Scan(&k.day, &k.category, &n); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		counts[k] += n
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	live, err := queryClanMessages(db, `
+		SELECT `+clanMessageColumns+`
+		FROM clan_messages
+		WHERE timestamp >= ? AND timestamp < ?
+	`, since.UTC().Format(archiveDay), until.UTC().Format(archiveDay))
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range live {
+		counts[key{m.Timestamp.UTC().Format(archiveDay), ClassifyClanMessage(m.Message)}]++
+	}
+
+	order := make(map[EventCategory]int, len(EventCategories))
+	for i, c := range EventCategories {
+		order[c] = i
+	}
+	results := make([]DailyEventCount, 0, len(counts))
+	for k, n := range counts {
+		day, _ := time.Parse(archiveDay, k.day)
+		results = append(results, DailyEventCount{Day: day, Category: k.category, Count: n})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if !results[i].Day.Equal(results[j].Day) {
+			return results[i].Day.Before(results[j].Day)
+		}
+		return order[results[i].Category] < order[results[j].Category]
+	})
+	return results, nil
+}
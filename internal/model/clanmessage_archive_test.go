@@ -0,0 +1,258 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// syntheticLines cycles through one line of each category.
+var syntheticLines = []string{
+	"player%d added 5x Godly key.",
+	"player%d joined the clan.",
+	"player%d was promoted to Officer.",
+	"player%d killed a boss.",
+}
+
+// newLegacyClanMessagesDB opens a database holding a clan_messages table as it looked before
+// retention existed, with no indexes beyond the unique one, filled with n synthetic lines
+// spread over the days before end. Every 1000th line is pending and every 1500th dead-lettered.
+func newLegacyClanMessagesDB(t *testing.T, n int, end time.Time, days int) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err := MigrateClanMessages(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := MigrateClanMessageDelivery(db); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt, err := tx.Prepare(`INSERT INTO clan_messages (clan_name, member_username, message, timestamp, message_sent) VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := time.Duration(days) * 24 * time.Hour / time.Duration(n)
+	start := end.Add(-time.Duration(days) * 24 * time.Hour)
+	for i := 0; i < n; i++ {
+		state := DeliverySent
+		switch {
+		case i%1000 == 0:
+			state = DeliveryPending
+		case i%1500 == 0:
+			state = DeliveryFailed
+		}
+		ts := start.Add(time.Duration(i) * step).UTC().Format(time.RFC3339)
+		msg := fmt.Sprintf(syntheticLines[i%len(syntheticLines)], i%50)
+		if _, err := stmt.Exec(ClanName, fmt.Sprintf("player%d", i%50), msg, ts, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = stmt.Close()
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// queryPlan returns the EXPLAIN QUERY PLAN details of a query.
+func queryPlan(t *testing.T, db *sql.DB, query string, args ...interface{}) string {
+	t.Helper()
+	rows, err := db.Query("EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var details []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			t.Fatal(err)
+		}
+		details = append(details, detail)
+	}
+	return strings.Join(details, "; ")
+}
+
+func TestClanMessageRetentionOnLargeDataset(t *testing.T) {
+	if testing.Short() {
+		t.Skip("large synthetic dataset")
+	}
+
+	const n, days = 60000, 200
+	end := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	db := newLegacyClanMessagesDB(t, n, end, days)
+
+	// the migration adds the indexes to the existing table
+	if err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := Migrate(db); err != nil {
+		t.Fatalf("second migration: %v", err)
+	}
+
+	now := end.Format(time.RFC3339)
+	plans := map[string]string{
+		"pending scan":  queryPlan(t, db, getMessagesQuery, DeliveryPending, now, 50),
+		"time range":    queryPlan(t, db, `SELECT message FROM clan_messages WHERE timestamp >= ? AND timestamp < ?`, now, now),
+		"member recent": queryPlan(t, db, `SELECT member_username, MAX(timestamp) FROM clan_messages GROUP BY member_username`),
+	}
+	for name, want := range map[string]string{
+		"pending scan":  "idx_clan_messages_delivery",
+		"time range":    "idx_clan_messages_timestamp",
+		"member recent": "idx_clan_messages_member",
+	} {
+		if !strings.Contains(plans[name], want) || strings.Contains(plans[name], "TEMP B-TREE") {
+			t.Errorf("%s plan = %q, want %s without a temp b-tree", name, plans[name], want)
+		}
+	}
+
+	before := end.AddDate(0, 0, -90)
+	var wantArchived, wantKept int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM clan_messages WHERE message_sent = ? AND timestamp < ?`,
+		DeliverySent, before.Format(time.RFC3339)).Scan(&wantArchived); err != nil {
+		t.Fatal(err)
+	}
+	wantKept = n - wantArchived
+
+	countsBefore, err := DailyEventCounts(db, end.AddDate(0, 0, -days-1), end)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ArchiveClanMessages(db, before, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Messages != int64(wantArchived) {
+		t.Errorf("archived %d lines, want %d", result.Messages, wantArchived)
+	}
+
+	var kept, oldUnsent, oldSent int
+	_ = db.QueryRow(`SELECT COUNT(*) FROM clan_messages`).Scan(&kept)
+	_ = db.QueryRow(`SELECT COUNT(*) FROM clan_messages WHERE message_sent <> ? AND timestamp < ?`,
+		DeliverySent, before.Format(time.RFC3339)).Scan(&oldUnsent)
+	_ = db.QueryRow(`SELECT COUNT(*) FROM clan_messages WHERE message_sent = ? AND timestamp < ?`,
+		DeliverySent, before.Format(time.RFC3339)).Scan(&oldSent)
+	if kept != wantKept || oldSent != 0 || oldUnsent == 0 {
+		t.Errorf("kept %d lines (want %d), %d old sent, %d old pending or failed", kept, wantKept, oldSent, oldUnsent)
+	}
+
+	// the daily counts don't change when lines move into the archive
+	countsAfter, err := DailyEventCounts(db, end.AddDate(0, 0, -days-1), end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(countsAfter) != len(countsBefore) {
+		t.Fatalf("%d daily counts after archiving, %d before", len(countsAfter), len(countsBefore))
+	}
+	total := 0
+	for i := range countsBefore {
+		if countsAfter[i] != countsBefore[i] {
+			t.Errorf("count %d = %+v after archiving, %+v before", i, countsAfter[i], countsBefore[i])
+			break
+		}
+		total += countsAfter[i].Count
+	}
+	if total != n {
+		t.Errorf("daily counts total %d, want %d", total, n)
+	}
+
+	// an archived day reads back intact
+	day := before.AddDate(0, 0, -30)
+	archived, err := ReadClanMessageArchive(db, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archived) == 0 {
+		t.Fatal("no archived lines for", day)
+	}
+	for _, m := range archived {
+		if m.Timestamp.UTC().Format(archiveDay) != day.Format(archiveDay) || !m.MessageSent || m.ClanName != ClanName {
+			t.Fatalf("archived line = %+v", m)
+		}
+	}
+
+	// archived lines fetched again are not stored a second time; new ones are
+	inserted, err := InsertClanMessage(db, archived[0])
+	if err != nil || inserted {
+		t.Errorf("re-inserting an archived line: inserted %v, %v", inserted, err)
+	}
+	inserted, err = InsertClanMessage(db, ClanMessage{ClanName: ClanName, MemberUsername: "new", Message: "new joined the clan.", Timestamp: end})
+	if err != nil || !inserted {
+		t.Errorf("inserting a new line: inserted %v, %v", inserted, err)
+	}
+
+	// a second run finds nothing left to archive
+	again, err := ArchiveClanMessages(db, before, 1000)
+	if err != nil || again.Messages != 0 {
+		t.Errorf("second run = %+v, %v", again, err)
+	}
+}
+
+func TestArchiveClanMessagesKeepsUndelivered(t *testing.T) {
+	db := newTestDB(t)
+	old := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	sent := insertTestMessage(t, db, "a added 1x Gold.", old)
+	pending := insertTestMessage(t, db, "b joined the clan.", old.Add(time.Minute))
+	recent := insertTestMessage(t, db, "c left the clan.", old.AddDate(0, 1, 0))
+	if err := MarkMessagesSent(db, []int64{sent, recent}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ArchiveClanMessages(db, old.AddDate(0, 0, 7), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Messages != 1 || result.Batches != 1 {
+		t.Errorf("result = %+v", result)
+	}
+
+	msgs, err := GetMessages(db, old.AddDate(1, 0, 0), 10)
+	if err != nil || len(msgs) != 1 || msgs[0].ID != pending {
+		t.Errorf("pending after archiving = %+v, %v", msgs, err)
+	}
+
+	// the archived line still counts as the member's activity
+	if _, err := db.Exec(`UPDATE clan_messages SET member_username = 'other'`); err != nil {
+		t.Fatal(err)
+	}
+	seen, err := LastClanLogActivity(db)
+	if err != nil || !seen["tester"].Equal(old) {
+		t.Errorf("tester last seen = %s, %v; want the archived line at %s", seen["tester"], err, old)
+	}
+
+	counts, err := DailyEventCounts(db, old, old.AddDate(0, 2, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DailyEventCount{
+		{Day: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), Category: CategoryVault, Count: 1},
+		{Day: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), Category: CategoryRoster, Count: 1},
+		{Day: time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC), Category: CategoryRoster, Count: 1},
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("counts = %+v", counts)
+	}
+	for i := range want {
+		if !counts[i].Day.Equal(want[i].Day) || counts[i].Category != want[i].Category || counts[i].Count != want[i].Count {
+			t.Errorf("count %d = %+v, want %+v", i, counts[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DeliveryState is the relay state of a clan message, stored in clan_messages.message_sent.
+type DeliveryState int
+
+const (
+	DeliveryPending DeliveryState = 0 // waiting to be posted, possibly after a backoff
+	DeliverySent    DeliveryState = 1
+	DeliveryFailed  DeliveryState = 2 // gave up after too many attempts; requeue to retry
+)
+
+func (s DeliveryState) String() string {
+	switch s {
+	case DeliveryPending:
+		return "pending"
+	case DeliverySent:
+		return "sent"
+	case DeliveryFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// deliveryColumns are added to clan_messages by MigrateClanMessageDelivery.
+var deliveryColumns = []struct{ name, definition string }{
+	{"attempts", "INTEGER NOT NULL DEFAULT 0"},
+	{"last_error", "TEXT NOT NULL DEFAULT ''"},
+	{"next_attempt_at", "TEXT"},
+}
+
+// MigrateClanMessageDelivery adds the delivery bookkeeping columns to clan_messages when missing.
+func MigrateClanMessageDelivery(db *sql.DB) error {
+	for _, col := range deliveryColumns {
+		exists, err := columnExists(db, "clan_messages", col.name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.Exec("ALTER TABLE clan_messages ADD COLUMN " + col.name + " " + col.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnExists reports whether table has the named column.
+func columnExists(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// ScheduleMessageRetry records a failed delivery attempt and when to try again.
+func ScheduleMessageRetry(db *sql.DB, id int64, attempts int, lastError string, next time.Time) error {
+	_, err := db.Exec(`
+        UPDATE clan_messages
+        SET attempts = ?, last_error = ?, next_attempt_at = ?
+        WHERE id = ?
+    `, attempts, lastError, next.UTC().Format(time.RFC3339), id)
+	return err
+}
+
+// MarkMessageFailed records the final failed attempt and moves the message to the failed state.
+func MarkMessageFailed(db *sql.DB, id int64, attempts int, lastError string) error {
+	_, err := db.Exec(`
+        UPDATE clan_messages
+        SET message_sent = ?, attempts = ?, last_error = ?, next_attempt_at = NULL
+        WHERE id = ?
+    `, DeliveryFailed, attempts, lastError, id)
+	return err
+}
+
+// ListFailedMessages returns up to limit dead-lettered messages, oldest first.
+func ListFailedMessages(db *sql.DB, limit int) ([]ClanMessage, error) {
+	query := `
+        SELECT ` + clanMessageColumns + `
+        FROM clan_messages
+        WHERE message_sent = ?
+        ORDER BY timestamp ASC
+        LIMIT ?
+    `
+	return queryClanMessages(db, query, DeliveryFailed, limit)
+}
+
+// CountFailedMessages returns how many messages are dead-lettered.
+func CountFailedMessages(db *sql.DB) (int, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM clan_messages WHERE message_sent = ?`, DeliveryFailed).Scan(&n)
+	return n, err
+}
+
+// RequeueFailedMessage moves one dead-lettered message back to pending with a fresh attempt count.
+// It reports whether a failed message with that ID existed.
+func RequeueFailedMessage(db *sql.DB, id int64) (bool, error) {
+	res, err := db.Exec(`
+        UPDATE clan_messages
+        SET message_sent = ?, attempts = 0, last_error = '', next_attempt_at = NULL
+        WHERE id = ? AND message_sent = ?
+    `, DeliveryPending, id, DeliveryFailed)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// RequeueAllFailedMessages moves every dead-lettered message back to pending and returns how many moved.
+func RequeueAllFailedMessages(db *sql.DB) (int64, error) {
+	res, err := db.Exec(`
+        UPDATE clan_messages
+        SET message_sent = ?, attempts = 0, last_error = '', next_attempt_at = NULL
+        WHERE message_sent = ?
+    `, DeliveryPending, DeliveryFailed)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func insertTestMessage(t *testing.T, db *sql.DB, text string, at time.Time) int64 {
+	t.Helper()
+	if _, err := InsertClanMessage(db, ClanMessage{ClanName: "KlutzCo", MemberUsername: "tester", Message: text, Timestamp: at}); err != nil {
+		t.Fatal(err)
+	}
+	var id int64
+	if err := db.QueryRow(`SELECT id FROM clan_messages WHERE message = ?`, text).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func messageIDs(msgs []ClanMessage) []int64 {
+	ids := make([]int64, 0, len(msgs))
+	for _, m := range msgs {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestDeliveryRetryAndDeadLetter(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	a := insertTestMessage(t, db, "a joined the clan.", now.Add(-2*time.Minute))
+	b := insertTestMessage(t, db, "b joined the clan.", now.Add(-time.Minute))
+
+	msgs, err := GetMessages(db, now, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := messageIDs(msgs); len(got) != 2 {
+		t.Fatalf("pending = %v, want both messages", got)
+	}
+
+	// a fails once and backs off for five minutes
+	if err := ScheduleMessageRetry(db, a, 1, "HTTP 500", now.Add(5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	msgs, _ = GetMessages(db, now, 10)
+	if got := messageIDs(msgs); len(got) != 1 || got[0] != b {
+		t.Errorf("pending during backoff = %v, want only %d", got, b)
+	}
+	msgs, _ = GetMessages(db, now.Add(5*time.Minute), 10)
+	if len(msgs) != 2 || msgs[0].Attempts != 1 || msgs[0].LastError != "HTTP 500" {
+		t.Errorf("pending after backoff = %+v, want a with one attempt first", msgs)
+	}
+
+	// a gives up
+	if err := MarkMessageFailed(db, a, 5, "HTTP 400: message too long"); err != nil {
+		t.Fatal(err)
+	}
+	msgs, _ = GetMessages(db, now.Add(time.Hour), 10)
+	if got := messageIDs(msgs); len(got) != 1 || got[0] != b {
+		t.Errorf("pending after dead-letter = %v, want only %d", got, b)
+	}
+
+	failed, err := ListFailedMessages(db, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0].ID != a || failed[0].State != DeliveryFailed || failed[0].Attempts != 5 {
+		t.Fatalf("failed = %+v, want message %d with 5 attempts", failed, a)
+	}
+
+	// requeue puts it back with a clean slate
+	ok, err := RequeueFailedMessage(db, a)
+	if err != nil || !ok {
+		t.Fatalf("RequeueFailedMessage = %v, %v", ok, err)
+	}
+	if ok, _ := RequeueFailedMessage(db, b); ok {
+		t.Error("requeued a message that was not failed")
+	}
+	msgs, _ = GetMessages(db, now, 10)
+	if len(msgs) != 2 || msgs[0].ID != a || msgs[0].Attempts != 0 || msgs[0].LastError != "" {
+		t.Errorf("pending after requeue = %+v", msgs)
+	}
+}
+
+func TestRequeueAllFailedMessages(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, text := range []string{"x left the clan.", "y left the clan.", "z left the clan."} {
+		id := insertTestMessage(t, db, text, now.Add(time.Duration(i)*time.Minute))
+		if i < 2 {
+			if err := MarkMessageFailed(db, id, 5, "boom"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if n, _ := CountFailedMessages(db); n != 2 {
+		t.Fatalf("CountFailedMessages = %d, want 2", n)
+	}
+	n, err := RequeueAllFailedMessages(db)
+	if err != nil || n != 2 {
+		t.Fatalf("RequeueAllFailedMessages = %d, %v; want 2", n, err)
+	}
+	if n, _ := CountFailedMessages(db); n != 0 {
+		t.Errorf("CountFailedMessages after requeue = %d, want 0", n)
+	}
+}
+
+func TestMigrateClanMessageDeliveryUpgradesOldTable(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	// the table as it existed before delivery tracking
+	if _, err := db.Exec(createTableQuery); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO clan_messages (clan_name, member_username, message, timestamp, message_sent) VALUES ('KlutzCo', 'old', 'old line', '2026-01-01T00:00:00Z', 1)`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	// running it again must be a no-op
+	if err := Migrate(db); err != nil {
+		t.Fatalf("second migrate: %v", err)
+	}
+
+	var attempts int
+	var lastError string
+	if err := db.QueryRow(`SELECT attempts, last_error FROM clan_messages WHERE member_username = 'old'`).Scan(&attempts, &lastError); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 0 || lastError != "" {
+		t.Errorf("migrated row attempts=%d last_error=%q, want defaults", attempts, lastError)
+	}
+}
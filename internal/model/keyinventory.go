@@ -0,0 +1,94 @@
+package model
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// KeyVaultHolder is the holder name used for keys sitting in the clan vault.
+// Member holders use their Idle Clans game name.
+const KeyVaultHolder = "@vault"
+
+const createKeyInventoryTableQuery = `
+CREATE TABLE IF NOT EXISTS key_inventory (
+    holder TEXT NOT NULL,
+    key_name TEXT NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (holder, key_name)
+);
+`
+
+// CreateKeyInventoryTable creates the key_inventory table if it does not exist.
+func CreateKeyInventoryTable(db *sql.DB) error {
+	_, err := db.Exec(createKeyInventoryTableQuery)
+	return err
+}
+
+// SetKeyCount records the number of keys of the given kind a holder owns.
+// Key names are stored lowercase to match KeysInformation.
+func SetKeyCount(db *sql.DB, holder, keyName string, count int) error {
+	if count < 0 {
+		count = 0
+	}
+	query := `
+		INSERT OR REPLACE INTO key_inventory (holder, key_name, count, updated_at)
+		VALUES (?, ?, ?, ?)
+	`
+	_, err := db.Exec(query, holder, strings.ToLower(keyName), count, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// AdjustKeyCount adds delta to the holder's key count, never going below zero.
+func AdjustKeyCount(db *sql.DB, holder, keyName string, delta int) error {
+	query := `
+		INSERT INTO key_inventory (holder, key_name, count, updated_at)
+		VALUES (?, ?, MAX(?, 0), ?)
+		ON CONFLICT (holder, key_name) DO UPDATE SET
+		    count = MAX(key_inventory.count + ?, 0),
+		    updated_at = excluded.updated_at
+	`
+	_, err := db.Exec(query, holder, strings.ToLower(keyName), delta, time.Now().UTC().Format(time.RFC3339), delta)
+	return err
+}
+
+// GetKeyCounts returns the key counts recorded for a single holder, keyed by key name.
+func GetKeyCounts(db *sql.DB, holder string) (map[string]int, error) {
+	rows, err := db.Query(`SELECT key_name, count FROM key_inventory WHERE holder = ? AND count > 0`, holder)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var count int
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, err
+		}
+		counts[name] = count
+	}
+	return counts, rows.Err()
+}
+
+// GetKeyPool returns the clan-wide total of each key across all holders, vault included.
+func GetKeyPool(db *sql.DB) (map[string]int, error) {
+	rows, err := db.Query(`SELECT key_name, SUM(count) FROM key_inventory GROUP BY key_name HAVING SUM(count) > 0`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pool := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var total int
+		if err := rows.Scan(&name, &total); err != nil {
+			return nil, err
+		}
+		pool[name] = total
+	}
+	return pool, rows.Err()
+}
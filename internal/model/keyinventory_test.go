@@ -0,0 +1,84 @@
+package model
+
+import "testing"
+
+func TestAdjustKeyCount(t *testing.T) {
+	db := newTestDB(t)
+
+	// a deposit moves keys from the member's recorded count into the vault
+	if err := SetKeyCount(db, "guildan", "Godly", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := AdjustKeyCount(db, "guildan", "godly", -3); err != nil {
+		t.Fatal(err)
+	}
+	if err := AdjustKeyCount(db, KeyVaultHolder, "godly", 3); err != nil {
+		t.Fatal(err)
+	}
+
+	member, err := GetKeyCounts(db, "guildan")
+	if err != nil || member["godly"] != 2 {
+		t.Errorf("member keys = %v, %v; want 2 godly", member, err)
+	}
+	vault, err := GetKeyCounts(db, KeyVaultHolder)
+	if err != nil || vault["godly"] != 3 {
+		t.Errorf("vault keys = %v, %v; want 3 godly", vault, err)
+	}
+
+	// withdrawing more than recorded empties the vault instead of going negative
+	if err := AdjustKeyCount(db, KeyVaultHolder, "godly", -10); err != nil {
+		t.Fatal(err)
+	}
+	// a withdrawal by a member who never used /keypool have starts them at the withdrawn count,
+	// and a deposit by one starts them at zero
+	if err := AdjustKeyCount(db, "ImaKlutz", "stone", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := AdjustKeyCount(db, "yothos", "stone", -1); err != nil {
+		t.Fatal(err)
+	}
+
+	if vault, _ := GetKeyCounts(db, KeyVaultHolder); len(vault) != 0 {
+		t.Errorf("vault keys after overdrawing = %v, want none", vault)
+	}
+	if keys, _ := GetKeyCounts(db, "ImaKlutz"); keys["stone"] != 2 {
+		t.Errorf("ImaKlutz keys = %v, want 2 stone", keys)
+	}
+	if keys, _ := GetKeyCounts(db, "yothos"); len(keys) != 0 {
+		t.Errorf("yothos keys = %v, want none", keys)
+	}
+}
+
+func TestGetKeyPool(t *testing.T) {
+	db := newTestDB(t)
+
+	for _, c := range []struct {
+		holder, key string
+		count       int
+	}{
+		{"guildan", "godly", 2},
+		{"ImaKlutz", "godly", 1},
+		{KeyVaultHolder, "godly", 4},
+		{"guildan", "stone", 0},
+		{KeyVaultHolder, "mountain", 1},
+	} {
+		if err := SetKeyCount(db, c.holder, c.key, c.count); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pool, err := GetKeyPool(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// members and the vault add up; keys nobody holds are left out
+	want := map[string]int{"godly": 7, "mountain": 1}
+	if len(pool) != len(want) {
+		t.Errorf("pool = %v, want %v", pool, want)
+	}
+	for key, n := range want {
+		if pool[key] != n {
+			t.Errorf("pool[%s] = %d, want %d", key, pool[key], n)
+		}
+	}
+}
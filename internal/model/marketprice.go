@@ -0,0 +1,212 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PriceResolution tells whether a market price row is a raw snapshot or a daily rollup.
+type PriceResolution string
+
+const (
+	PriceResolutionRaw   PriceResolution = "raw"
+	PriceResolutionDaily PriceResolution = "daily"
+)
+
+// MarketPrice is a stored market price observation for a single item.
+type MarketPrice struct {
+	ItemID          int             `json:"itemId"`
+	Timestamp       time.Time       `json:"timestamp"`
+	LowestSellPrice float64         `json:"lowestSellPrice"`
+	AveragePrice    float64         `json:"averagePrice"`
+	Resolution      PriceResolution `json:"resolution"`
+}
+
+const createMarketPricesTableQuery = `
+CREATE TABLE IF NOT EXISTS market_prices (
+    item_id INTEGER NOT NULL,
+    timestamp DATETIME NOT NULL,
+    lowest_sell_price REAL NOT NULL,
+    average_price REAL NOT NULL DEFAULT 0,
+    resolution TEXT NOT NULL DEFAULT 'raw',
+    PRIMARY KEY (item_id, resolution, timestamp)
+);
+
+CREATE INDEX IF NOT EXISTS idx_market_prices_timestamp ON market_prices (timestamp);
+`
+
+// CreateMarketPricesTable creates the market_prices table if it does not exist.
+func CreateMarketPricesTable(db *sql.DB) error {
+	_, err := db.Exec(createMarketPricesTableQuery)
+	return err
+}
+
+// InsertMarketSnapshot stores one raw price row per item, all sharing the same timestamp.
+func InsertMarketSnapshot(db *sql.DB, takenAt time.Time, prices []MarketPrice) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO market_prices (item_id, timestamp, lowest_sell_price, average_price, resolution)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	ts := takenAt.UTC().Format(time.RFC3339)
+	for _, p := range prices {
+		if _, err := stmt.Exec(p.ItemID, ts, p.LowestSellPrice, p.AveragePrice, PriceResolutionRaw); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetMarketPriceHistory returns every stored observation of an item since the given time,
+// raw and daily rows alike, oldest first.
+func GetMarketPriceHistory(db *sql.DB, itemID int, since time.Time) ([]MarketPrice, error) {
+	query := `
+		SELECT item_id, timestamp, lowest_sell_price, average_price, resolution
+		FROM market_prices
+		WHERE item_id = ? AND timestamp >= ?
+		ORDER BY timestamp ASC
+	`
+	rows, err := db.Query(query, itemID, since.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanMarketPrices(rows)
+}
+
+// GetLatestMarketSnapshot returns the most recent raw snapshot for every item.
+// The returned time is the snapshot timestamp, zero if nothing has been stored yet.
+func GetLatestMarketSnapshot(db *sql.DB) (time.Time, []MarketPrice, error) {
+	return getMarketSnapshot(db, `SELECT MAX(timestamp) FROM market_prices WHERE resolution = ?`, PriceResolutionRaw)
+}
+
+// GetMarketSnapshotAt returns the most recent raw snapshot taken at or before the given time.
+// The returned time is the snapshot timestamp, zero if there is none.
+func GetMarketSnapshotAt(db *sql.DB, at time.Time) (time.Time, []MarketPrice, error) {
+	return getMarketSnapshot(db, `SELECT MAX(timestamp) FROM market_prices WHERE resolution = ? AND timestamp <= ?`,
+		PriceResolutionRaw, at.UTC().Format(time.RFC3339))
+}
+
+// GetMarketSnapshotAfter returns the oldest raw snapshot taken at or after the given time.
+// The returned time is the snapshot timestamp, zero if there is none.
+func GetMarketSnapshotAfter(db *sql.DB, at time.Time) (time.Time, []MarketPrice, error) {
+	return getMarketSnapshot(db, `SELECT MIN(timestamp) FROM market_prices WHERE resolution = ? AND timestamp >= ?`,
+		PriceResolutionRaw, at.UTC().Format(time.RFC3339))
+}
+
+// GetMarketDailyPrices returns the daily rollup of the UTC day containing day, empty until
+// DownsampleMarketPrices has rolled that day's raw snapshots up.
+func GetMarketDailyPrices(db *sql.DB, day time.Time) ([]MarketPrice, error) {
+	rows, err := db.Query(`
+		SELECT item_id, timestamp, lowest_sell_price, average_price, resolution
+		FROM market_prices
+		WHERE resolution = ? AND timestamp = ?
+	`, PriceResolutionDaily, day.UTC().Truncate(24*time.Hour).Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanMarketPrices(rows)
+}
+
+// LowestSellPrices indexes the lowest sell price of stored prices by item ID.
+func LowestSellPrices(prices []MarketPrice) map[int]float64 {
+	m := make(map[int]float64, len(prices))
+	for _, p := range prices {
+		m[p.ItemID] = p.LowestSellPrice
+	}
+	return m
+}
+
+// getMarketSnapshot loads every raw row sharing the timestamp selected by tsQuery.
+func getMarketSnapshot(db *sql.DB, tsQuery string, args ...interface{}) (time.Time, []MarketPrice, error) {
+	var ts sql.NullString
+	err := db.QueryRow(tsQuery, args...).Scan(&ts)
+	if err != nil || !ts.Valid {
+		return time.Time{}, nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT item_id, timestamp, lowest_sell_price, average_price, resolution
+		FROM market_prices
+		WHERE resolution = ? AND timestamp = ?
+	`, PriceResolutionRaw, ts.String)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	defer rows.Close()
+
+	prices, err := scanMarketPrices(rows)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	takenAt, _ := time.Parse(time.RFC3339, ts.String)
+	return takenAt, prices, nil
+}
+
+// DownsampleMarketPrices rolls raw snapshots older than rawCutoff into one averaged row
+// per item and day, then drops daily rows older than dailyCutoff.
+// rawCutoff is truncated to a day boundary so only complete days get rolled up.
+func DownsampleMarketPrices(db *sql.DB, rawCutoff, dailyCutoff time.Time) error {
+	rawCutoff = rawCutoff.UTC().Truncate(24 * time.Hour)
+	raw := rawCutoff.Format(time.RFC3339)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT OR REPLACE INTO market_prices (item_id, timestamp, lowest_sell_price, average_price, resolution)
+		SELECT item_id, substr(timestamp, 1, 10) || 'T00:00:00Z', AVG(lowest_sell_price), AVG(average_price), ?
+		FROM market_prices
+		WHERE resolution = ? AND timestamp < ?
+		GROUP BY item_id, substr(timestamp, 1, 10)
+	`, PriceResolutionDaily, PriceResolutionRaw, raw)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM market_prices WHERE resolution = ? AND timestamp < ?`, PriceResolutionRaw, raw); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM market_prices WHERE resolution = ? AND timestamp < ?`,
+		PriceResolutionDaily, dailyCutoff.UTC().Format(time.RFC3339)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanMarketPrices(rows *sql.Rows) ([]MarketPrice, error) {
+	var results []MarketPrice
+	for rows.Next() {
+		var p MarketPrice
+		var ts string
+		if err := rows.Scan(&p.ItemID, &ts, &p.LowestSellPrice, &p.AveragePrice, &p.Resolution); err != nil {
+			return nil, err
+		}
+		if t, err := time.Parse(time.RFC3339, ts); err == nil {
+			p.Timestamp = t
+		}
+		results = append(results, p)
+	}
+	return results, rows.Err()
+}
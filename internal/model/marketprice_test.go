@@ -0,0 +1,127 @@
+package model
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// newTestDB opens a migrated in-memory database.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a single connection keeps every query on the same in-memory database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestDownsampleMarketPrices(t *testing.T) {
+	db := newTestDB(t)
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := []struct {
+		at    time.Time
+		price float64
+	}{
+		{day.Add(1 * time.Hour), 100},
+		{day.Add(13 * time.Hour), 200},
+		{day.Add(25 * time.Hour), 400}, // next day, still raw
+	}
+	for _, s := range snapshots {
+		if err := InsertMarketSnapshot(db, s.at, []MarketPrice{{ItemID: 1, LowestSellPrice: s.price, AveragePrice: s.price}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a daily row far in the past that should be pruned
+	if _, err := db.Exec(`INSERT INTO market_prices (item_id, timestamp, lowest_sell_price, resolution) VALUES (1, '2025-01-01T00:00:00Z', 5, 'daily')`); err != nil {
+		t.Fatal(err)
+	}
+
+	// raw cutoff mid-way through the second day: only the first day is complete
+	if err := DownsampleMarketPrices(db, day.Add(30*time.Hour), day.AddDate(0, -1, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := GetMarketPriceHistory(db, 1, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("len(history) = %d, want 2: %+v", len(history), history)
+	}
+
+	daily := history[0]
+	if daily.Resolution != PriceResolutionDaily || !daily.Timestamp.Equal(day) || daily.LowestSellPrice != 150 {
+		t.Errorf("daily row = %+v, want daily average 150 at %s", daily, day)
+	}
+	if history[1].Resolution != PriceResolutionRaw || history[1].LowestSellPrice != 400 {
+		t.Errorf("raw row = %+v, want raw 400", history[1])
+	}
+}
+
+func TestGetLatestMarketSnapshot(t *testing.T) {
+	db := newTestDB(t)
+
+	takenAt, prices, err := GetLatestMarketSnapshot(db)
+	if err != nil || !takenAt.IsZero() || len(prices) != 0 {
+		t.Fatalf("empty table: got %s, %v, %v", takenAt, prices, err)
+	}
+
+	older := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	_ = InsertMarketSnapshot(db, older, []MarketPrice{{ItemID: 1, LowestSellPrice: 10}, {ItemID: 2, LowestSellPrice: 20}})
+	_ = InsertMarketSnapshot(db, newer, []MarketPrice{{ItemID: 1, LowestSellPrice: 11}, {ItemID: 2, LowestSellPrice: 21}})
+
+	takenAt, prices, err = GetLatestMarketSnapshot(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !takenAt.Equal(newer) {
+		t.Errorf("takenAt = %s, want %s", takenAt, newer)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("len(prices) = %d, want 2", len(prices))
+	}
+	for _, p := range prices {
+		if p.LowestSellPrice != float64(p.ItemID)*10+1 {
+			t.Errorf("item %d price = %v, want newest snapshot", p.ItemID, p.LowestSellPrice)
+		}
+	}
+}
+
+func TestGetMarketSnapshotAt(t *testing.T) {
+	db := newTestDB(t)
+
+	first := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	_ = InsertMarketSnapshot(db, first, []MarketPrice{{ItemID: 1, LowestSellPrice: 10}})
+	_ = InsertMarketSnapshot(db, second, []MarketPrice{{ItemID: 1, LowestSellPrice: 20}})
+
+	takenAt, prices, err := GetMarketSnapshotAt(db, second.Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !takenAt.Equal(first) || len(prices) != 1 || prices[0].LowestSellPrice != 10 {
+		t.Errorf("GetMarketSnapshotAt = %s %+v, want first snapshot", takenAt, prices)
+	}
+
+	takenAt, _, err = GetMarketSnapshotAt(db, first.Add(-time.Minute))
+	if err != nil || !takenAt.IsZero() {
+		t.Errorf("before any snapshot: got %s, %v", takenAt, err)
+	}
+
+	takenAt, prices, err = GetMarketSnapshotAfter(db, first.Add(time.Minute))
+	if err != nil || !takenAt.Equal(second) || len(prices) != 1 || prices[0].LowestSellPrice != 20 {
+		t.Errorf("GetMarketSnapshotAfter = %s %+v, %v, want second snapshot", takenAt, prices, err)
+	}
+}
@@ -0,0 +1,365 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// Member is what the bot knows about a clan member beyond the clan log itself.
+type Member struct {
+	GameName  string    `json:"gameName"`
+	DiscordID string    `json:"discordId,omitempty"` // set once the member used /link
+	JoinedAt  time.Time `json:"joinedAt,omitempty"`  // latest "joined the clan" line
+	LeftAt    time.Time `json:"leftAt,omitempty"`    // latest "left the clan" line
+}
+
+// InClan reports whether the member's latest roster line is a join.
+func (m Member) InClan() bool {
+	return !m.JoinedAt.IsZero() && m.JoinedAt.After(m.LeftAt)
+}
+
+const createMembersTableQuery = `
+CREATE TABLE IF NOT EXISTS members (
+    game_name TEXT PRIMARY KEY,
+    discord_id TEXT NOT NULL DEFAULT '',
+    joined_at TEXT,
+    left_at TEXT,
+    updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_members_discord_id ON members (discord_id);
+
+CREATE TABLE IF NOT EXISTS link_requests (
+    game_name TEXT NOT NULL,
+    discord_id TEXT NOT NULL,
+    requested_at TEXT NOT NULL,
+    PRIMARY KEY (game_name, discord_id)
+);
+`
+
+// CreateMembersTable creates the members and link_requests tables if they do not exist.
+func CreateMembersTable(db *sql.DB) error {
+	_, err := db.Exec(createMembersTableQuery)
+	return err
+}
+
+// rosterChangeRe matches "player joined the clan." and "player left the clan."
+var rosterChangeRe = regexp.MustCompile(`^(.+?)\s+(joined|left)\s+the\s+clan\.?$`)
+
+// ParseRosterChange extracts the member of a join or leave line.
+func ParseRosterChange(message string) (gameName string, joined bool, ok bool) {
+	matches := rosterChangeRe.FindStringSubmatch(message)
+	if len(matches) != 3 {
+		return "", false, false
+	}
+	return matches[1], matches[2] == "joined", true
+}
+
+// ErrAlreadyLinked is returned when a game name is linked to another Discord account.
+var ErrAlreadyLinked = errors.New("game name is linked to another Discord account")
+
+// unlinkedDiscordID marks a game name an officer unlinked, so its static MemberToDiscordID
+// link no longer applies either.
+const unlinkedDiscordID = "-"
+
+// LinkRequest is a member's claim on a game name, waiting for an officer to approve it.
+type LinkRequest struct {
+	GameName    string    `json:"gameName"`
+	DiscordID   string    `json:"discordId"`
+	RequestedAt time.Time `json:"requestedAt"`
+}
+
+// LinkMember links a game name to a Discord account, replacing any earlier game name of that account.
+// Members go through RequestLink; this is for confirmed links only.
+func LinkMember(db *sql.DB, gameName, discordID string) error {
+	return linkMember(db, gameName, discordID, false)
+}
+
+// ForceLinkMember links a game name to a Discord account even when it is linked to
+// another account. It is the officer override for names claimed by the wrong member.
+func ForceLinkMember(db *sql.DB, gameName, discordID string) error {
+	return linkMember(db, gameName, discordID, true)
+}
+
+func linkMember(db *sql.DB, gameName, discordID string, force bool) error {
+	if !force {
+		if current, ok, err := LinkedDiscordID(db, gameName); err != nil {
+			return err
+		} else if ok && current != discordID {
+			return ErrAlreadyLinked
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := tx.Exec(`UPDATE members SET discord_id = '', updated_at = ? WHERE discord_id = ? AND game_name <> ?`,
+		now, discordID, gameName); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO members (game_name, discord_id, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (game_name) DO UPDATE SET discord_id = excluded.discord_id, updated_at = excluded.updated_at
+	`, gameName, discordID, now); err != nil {
+		return err
+	}
+	// the name and the account are settled, so their pending claims are too
+	if _, err := tx.Exec(`DELETE FROM link_requests WHERE game_name = ? OR discord_id = ?`, gameName, discordID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UnlinkMember removes the link of a game name, including a static MemberToDiscordID one.
+// It returns the Discord account that was linked, if any.
+func UnlinkMember(db *sql.DB, gameName string) (string, bool, error) {
+	discordID, ok, err := LinkedDiscordID(db, gameName)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	_, err = db.Exec(`
+		INSERT INTO members (game_name, discord_id, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (game_name) DO UPDATE SET discord_id = excluded.discord_id, updated_at = excluded.updated_at
+	`, gameName, unlinkedDiscordID, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return "", false, err
+	}
+	return discordID, true, nil
+}
+
+// RequestLink records a member's claim on a game name for an officer to approve, replacing
+// any earlier claim of that account. Names linked to another account return ErrAlreadyLinked.
+func RequestLink(db *sql.DB, gameName, discordID string) error {
+	if current, ok, err := LinkedDiscordID(db, gameName); err != nil {
+		return err
+	} else if ok && current != discordID {
+		return ErrAlreadyLinked
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM link_requests WHERE discord_id = ?`, discordID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO link_requests (game_name, discord_id, requested_at) VALUES (?, ?, ?)`,
+		gameName, discordID, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ApproveLinkRequest confirms a pending claim and links the name. It reports whether
+// the claim existed; a name linked to another account meanwhile returns ErrAlreadyLinked.
+func ApproveLinkRequest(db *sql.DB, gameName, discordID string) (bool, error) {
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM link_requests WHERE game_name = ? AND discord_id = ?`,
+		gameName, discordID).Scan(&n); err != nil || n == 0 {
+		return false, err
+	}
+	return true, LinkMember(db, gameName, discordID)
+}
+
+// RejectLinkRequest drops a pending claim. It reports whether the claim existed.
+func RejectLinkRequest(db *sql.DB, gameName, discordID string) (bool, error) {
+	res, err := db.Exec(`DELETE FROM link_requests WHERE game_name = ? AND discord_id = ?`, gameName, discordID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListLinkRequests returns the pending claims, oldest first.
+func ListLinkRequests(db *sql.DB) ([]LinkRequest, error) {
+	rows, err := db.Query(`SELECT game_name, discord_id, requested_at FROM link_requests ORDER BY requested_at, game_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []LinkRequest
+	for rows.Next() {
+		var r LinkRequest
+		var at sql.NullString
+		if err := rows.Scan(&r.GameName, &r.DiscordID, &at); err != nil {
+			return nil, err
+		}
+		r.RequestedAt = parseStoredTime(at)
+		requests = append(requests, r)
+	}
+	return requests, rows.Err()
+}
+
+// RecordMemberJoined stores when a member joined, keeping the latest date when lines arrive out of order.
+func RecordMemberJoined(db *sql.DB, gameName string, at time.Time) error {
+	return recordRosterDate(db, "joined_at", gameName, at)
+}
+
+// RecordMemberLeft stores when a member left, keeping the latest date when lines arrive out of order.
+func RecordMemberLeft(db *sql.DB, gameName string, at time.Time) error {
+	return recordRosterDate(db, "left_at", gameName, at)
+}
+
+func recordRosterDate(db *sql.DB, column, gameName string, at time.Time) error {
+	// RFC3339 UTC strings sort chronologically, so MAX keeps the latest date
+	_, err := db.Exec(`
+		INSERT INTO members (game_name, `+column+`, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT (game_name) DO UPDATE SET
+			`+column+` = MAX(COALESCE(`+column+`, ''), excluded.`+column+`),
+			updated_at = excluded.updated_at
+	`, gameName, at.UTC().Format(time.RFC3339), time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// GetMember returns the stored record of a game name. Members linked only through
+// MemberToDiscordID are returned with that Discord ID.
+func GetMember(db *sql.DB, gameName string) (Member, bool, error) {
+	m := Member{GameName: gameName}
+	var joined, left sql.NullString
+	err := db.QueryRow(`SELECT discord_id, joined_at, left_at FROM members WHERE game_name = ?`, gameName).
+		Scan(&m.DiscordID, &joined, &left)
+	found := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return Member{}, false, err
+	}
+
+	m.JoinedAt = parseStoredTime(joined)
+	m.LeftAt = parseStoredTime(left)
+	if m.DiscordID == unlinkedDiscordID {
+		m.DiscordID = ""
+		return m, found, nil
+	}
+	if m.DiscordID == "" {
+		if id, ok := MemberToDiscordID[gameName]; ok {
+			m.DiscordID = id
+			found = true
+		}
+	}
+	return m, found, nil
+}
+
+// LinkedDiscordID returns the Discord account linked to a game name,
+// falling back to the static MemberToDiscordID map unless an officer unlinked the name.
+func LinkedDiscordID(db *sql.DB, gameName string) (string, bool, error) {
+	var id string
+	err := db.QueryRow(`SELECT discord_id FROM members WHERE game_name = ? AND discord_id <> ''`, gameName).Scan(&id)
+	switch {
+	case err == nil && id == unlinkedDiscordID:
+		return "", false, nil
+	case err == nil:
+		return id, true, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return "", false, err
+	}
+	id, ok := MemberToDiscordID[gameName]
+	return id, ok, nil
+}
+
+// LinkedGameName returns the game name linked to a Discord account,
+// falling back to the static MemberToDiscordID map.
+func LinkedGameName(db *sql.DB, discordID string) (string, bool, error) {
+	var name string
+	err := db.QueryRow(`SELECT game_name FROM members WHERE discord_id = ?`, discordID).Scan(&name)
+	switch {
+	case err == nil:
+		return name, true, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return "", false, err
+	}
+	name, ok := GameNameForDiscordID(discordID)
+	if !ok {
+		return "", false, nil
+	}
+	// the static link only counts while the name wasn't unlinked or given to another account
+	id, linked, err := LinkedDiscordID(db, name)
+	if err != nil || !linked || id != discordID {
+		return "", false, err
+	}
+	return name, true, nil
+}
+
+// ListLinkedMembers returns the Discord account of every linked game name, including the
+// static MemberToDiscordID links that were not replaced with /link.
+func ListLinkedMembers(db *sql.DB) (map[string]string, error) {
+	linked := make(map[string]string, len(MemberToDiscordID))
+	for gameName, id := range MemberToDiscordID {
+		linked[gameName] = id
+	}
+
+	rows, err := db.Query(`SELECT game_name, discord_id FROM members WHERE discord_id <> ''`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var gameName, id string
+		if err := rows.Scan(&gameName, &id); err != nil {
+			return nil, err
+		}
+		if id == unlinkedDiscordID {
+			delete(linked, gameName)
+			continue
+		}
+		// an account relinked with /link drops its static game name
+		for name, staticID := range linked {
+			if staticID == id && name != gameName {
+				delete(linked, name)
+			}
+		}
+		linked[gameName] = id
+	}
+	return linked, rows.Err()
+}
+
+// RosterChangesBetween returns the members whose latest join or leave falls in [since, until),
+// alphabetically.
+func RosterChangesBetween(db *sql.DB, since, until time.Time) (joined, left []string, err error) {
+	from, to := since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339)
+	if joined, err = queryGameNames(db, `SELECT game_name FROM members WHERE joined_at >= ? AND joined_at < ? ORDER BY game_name`, from, to); err != nil {
+		return nil, nil, err
+	}
+	if left, err = queryGameNames(db, `SELECT game_name FROM members WHERE left_at >= ? AND left_at < ? ORDER BY game_name`, from, to); err != nil {
+		return nil, nil, err
+	}
+	return joined, left, nil
+}
+
+func queryGameNames(db *sql.DB, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func parseStoredTime(s sql.NullString) time.Time {
+	if !s.Valid || s.String == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, s.String)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,229 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRosterChange(t *testing.T) {
+	tests := []struct {
+		message string
+		name    string
+		joined  bool
+		ok      bool
+	}{
+		{"moraxam joined the clan.", "moraxam", true, true},
+		{"Big Klutz left the clan.", "Big Klutz", false, true},
+		{"guildan added 3x Godly key.", "", false, false},
+	}
+	for _, tt := range tests {
+		name, joined, ok := ParseRosterChange(tt.message)
+		if name != tt.name || joined != tt.joined || ok != tt.ok {
+			t.Errorf("ParseRosterChange(%q) = %q, %v, %v; want %q, %v, %v",
+				tt.message, name, joined, ok, tt.name, tt.joined, tt.ok)
+		}
+	}
+}
+
+func TestLinkMember(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := LinkMember(db, "newbie", "111"); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok, _ := LinkedDiscordID(db, "newbie"); !ok || id != "111" {
+		t.Errorf("LinkedDiscordID(newbie) = %q, %v; want 111", id, ok)
+	}
+
+	// another account can't take the name
+	if err := LinkMember(db, "newbie", "222"); !errors.Is(err, ErrAlreadyLinked) {
+		t.Errorf("linking a taken name: err = %v, want ErrAlreadyLinked", err)
+	}
+	// nor a name from the static map
+	if err := LinkMember(db, "guildan", "222"); !errors.Is(err, ErrAlreadyLinked) {
+		t.Errorf("linking a statically linked name: err = %v, want ErrAlreadyLinked", err)
+	}
+
+	// relinking moves the account to the new name
+	if err := LinkMember(db, "newbie2", "111"); err != nil {
+		t.Fatal(err)
+	}
+	if name, ok, _ := LinkedGameName(db, "111"); !ok || name != "newbie2" {
+		t.Errorf("LinkedGameName(111) = %q, %v; want newbie2", name, ok)
+	}
+	if _, ok, _ := LinkedDiscordID(db, "newbie"); ok {
+		t.Error("old name is still linked after relinking")
+	}
+
+	// static links still resolve
+	if name, ok, _ := LinkedGameName(db, MemberToDiscordID["guildan"]); !ok || name != "guildan" {
+		t.Errorf("LinkedGameName(static) = %q, %v; want guildan", name, ok)
+	}
+}
+
+func TestLinkRequests(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := RequestLink(db, "newbie", "111"); err != nil {
+		t.Fatal(err)
+	}
+	// a request is not a link
+	if _, ok, _ := LinkedDiscordID(db, "newbie"); ok {
+		t.Error("requested name is linked before approval")
+	}
+	// a later request of the same account replaces the earlier one
+	if err := RequestLink(db, "newb", "111"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RequestLink(db, "newb", "222"); err != nil {
+		t.Fatal(err)
+	}
+	requests, err := ListLinkRequests(db)
+	if err != nil || len(requests) != 2 || requests[0].GameName != "newb" || requests[1].GameName != "newb" {
+		t.Fatalf("ListLinkRequests = %+v, %v", requests, err)
+	}
+
+	if found, err := ApproveLinkRequest(db, "newbie", "111"); err != nil || found {
+		t.Errorf("approving a replaced request = %v, %v; want false", found, err)
+	}
+	if found, err := ApproveLinkRequest(db, "newb", "111"); err != nil || !found {
+		t.Fatalf("ApproveLinkRequest = %v, %v", found, err)
+	}
+	if id, ok, _ := LinkedDiscordID(db, "newb"); !ok || id != "111" {
+		t.Errorf("LinkedDiscordID(newb) = %q, %v; want 111", id, ok)
+	}
+	// the competing claim on the name is settled by the approval
+	if requests, _ := ListLinkRequests(db); len(requests) != 0 {
+		t.Errorf("requests left after approval = %+v", requests)
+	}
+
+	// linked names, static ones included, can't be claimed by another account
+	if err := RequestLink(db, "newb", "222"); !errors.Is(err, ErrAlreadyLinked) {
+		t.Errorf("requesting a linked name: err = %v, want ErrAlreadyLinked", err)
+	}
+	if err := RequestLink(db, "guildan", "222"); !errors.Is(err, ErrAlreadyLinked) {
+		t.Errorf("requesting a statically linked name: err = %v, want ErrAlreadyLinked", err)
+	}
+
+	if err := RequestLink(db, "other", "333"); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := RejectLinkRequest(db, "other", "333"); err != nil || !found {
+		t.Errorf("RejectLinkRequest = %v, %v", found, err)
+	}
+	if found, _ := ApproveLinkRequest(db, "other", "333"); found {
+		t.Error("approved a rejected request")
+	}
+}
+
+func TestForceLinkAndUnlinkMember(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := LinkMember(db, "newbie", "111"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ForceLinkMember(db, "newbie", "222"); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok, _ := LinkedDiscordID(db, "newbie"); !ok || id != "222" {
+		t.Errorf("LinkedDiscordID after override = %q, %v; want 222", id, ok)
+	}
+	if _, ok, _ := LinkedGameName(db, "111"); ok {
+		t.Error("previous account is still linked after override")
+	}
+
+	previous, found, err := UnlinkMember(db, "newbie")
+	if err != nil || !found || previous != "222" {
+		t.Errorf("UnlinkMember = %q, %v, %v; want 222", previous, found, err)
+	}
+	if _, ok, _ := LinkedDiscordID(db, "newbie"); ok {
+		t.Error("name is still linked after unlinking")
+	}
+	if _, found, _ := UnlinkMember(db, "newbie"); found {
+		t.Error("unlinked a name twice")
+	}
+
+	// static links can be removed too, and the name can be linked again afterwards
+	staticID := MemberToDiscordID["guildan"]
+	if previous, found, err := UnlinkMember(db, "guildan"); err != nil || !found || previous != staticID {
+		t.Errorf("UnlinkMember(static) = %q, %v, %v; want %s", previous, found, err, staticID)
+	}
+	if _, ok, _ := LinkedGameName(db, staticID); ok {
+		t.Error("static account is still linked after unlinking")
+	}
+	if linked, _ := ListLinkedMembers(db); linked["guildan"] != "" {
+		t.Errorf("ListLinkedMembers still has guildan: %q", linked["guildan"])
+	}
+	if err := LinkMember(db, "guildan", "333"); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok, _ := LinkedDiscordID(db, "guildan"); !ok || id != "333" {
+		t.Errorf("LinkedDiscordID(guildan) = %q, %v; want 333", id, ok)
+	}
+}
+
+func TestRecordMemberRosterDates(t *testing.T) {
+	db := newTestDB(t)
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := RecordMemberJoined(db, "hopper", day); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordMemberLeft(db, "hopper", day.Add(24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	m, found, err := GetMember(db, "hopper")
+	if err != nil || !found {
+		t.Fatalf("GetMember = %v, %v", found, err)
+	}
+	if m.InClan() {
+		t.Error("member counts as in the clan after leaving")
+	}
+
+	// rejoining brings them back; an older join arriving late doesn't
+	if err := RecordMemberJoined(db, "hopper", day.Add(48*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordMemberJoined(db, "hopper", day); err != nil {
+		t.Fatal(err)
+	}
+	m, _, _ = GetMember(db, "hopper")
+	if !m.JoinedAt.Equal(day.Add(48*time.Hour)) || !m.InClan() {
+		t.Errorf("after rejoining: joined %s, in clan %v", m.JoinedAt, m.InClan())
+	}
+
+	if err := RecordMemberLeft(db, "quitter", day.Add(30*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	joined, left, err := RosterChangesBetween(db, day.Add(time.Hour), day.Add(72*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(joined) != 1 || joined[0] != "hopper" || len(left) != 2 || left[0] != "hopper" || left[1] != "quitter" {
+		t.Errorf("RosterChangesBetween = %v, %v", joined, left)
+	}
+}
+
+func TestWelcomeSettings(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, ok, _ := GetWelcomeSettings(db, "g1"); ok {
+		t.Fatal("settings exist before setup")
+	}
+	s := WelcomeSettings{GuildID: "g1", ChannelID: "c1", RoleID: "r1", WelcomeMessage: "Hi {member}"}
+	if err := SetWelcomeSettings(db, s); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := GetWelcomeSettings(db, "g1")
+	if err != nil || !ok || got != s {
+		t.Fatalf("GetWelcomeSettings = %+v, %v, %v; want %+v", got, ok, err, s)
+	}
+
+	if removed, err := DeleteWelcomeSettings(db, "g1"); err != nil || !removed {
+		t.Fatalf("DeleteWelcomeSettings = %v, %v", removed, err)
+	}
+	if all, _ := ListWelcomeSettings(db); len(all) != 0 {
+		t.Errorf("settings left after delete: %+v", all)
+	}
+}
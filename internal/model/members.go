@@ -1,5 +1,8 @@
 package model
 
+// ClanName is the Idle Clans clan this bot serves.
+const ClanName = "KlutzCo"
+
 // MemberToDiscord maps Idle Clans game names to Discord display names.
 var MemberToDiscord = map[string]string{
 	"ImaKlutz":  "ImaKlutz",
@@ -23,3 +26,13 @@ var MemberToDiscordID = map[string]string{
 	"g4m3f4c3":  "298522549661466625",
 	"Oliiviier": "350298028902711308",
 }
+
+// GameNameForDiscordID returns the Idle Clans game name linked to a Discord user ID.
+func GameNameForDiscordID(discordID string) (string, bool) {
+	for gameName, id := range MemberToDiscordID {
+		if id == discordID {
+			return gameName, true
+		}
+	}
+	return "", false
+}
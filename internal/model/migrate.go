@@ -10,10 +10,73 @@ func Migrate(db *sql.DB) error {
 		return err
 	}
 
+	// Add delivery bookkeeping columns to clan_messages
+	if err := MigrateClanMessageDelivery(db); err != nil {
+		return err
+	}
+
+	// Add clan_messages indexes and the archive and daily event count tables
+	if err := MigrateClanMessageRetention(db); err != nil {
+		return err
+	}
+
 	// Create scheduled_messages table
 	if err := CreateScheduledMessagesTable(db); err != nil {
 		return err
 	}
 
+	// Create key_inventory table
+	if err := CreateKeyInventoryTable(db); err != nil {
+		return err
+	}
+
+	// Create market_prices table
+	if err := CreateMarketPricesTable(db); err != nil {
+		return err
+	}
+
+	// Create price_alerts table
+	if err := CreatePriceAlertsTable(db); err != nil {
+		return err
+	}
+
+	// Create relay_routes table
+	if err := CreateRelayRoutesTable(db); err != nil {
+		return err
+	}
+
+	// Create members and welcome_settings tables
+	if err := CreateMembersTable(db); err != nil {
+		return err
+	}
+	if err := CreateWelcomeSettingsTable(db); err != nil {
+		return err
+	}
+
+	// Create rank_roles table
+	if err := CreateRankRolesTable(db); err != nil {
+		return err
+	}
+
+	// Create poll_responses table
+	if err := CreatePollResponsesTable(db); err != nil {
+		return err
+	}
+
+	// Create xp_snapshots table
+	if err := CreateXPSnapshotsTable(db); err != nil {
+		return err
+	}
+
+	// Create milestone_opt_outs table
+	if err := CreateMilestoneOptOutsTable(db); err != nil {
+		return err
+	}
+
+	// Create boss_poll_reactions table
+	if err := CreateBossPollReactionsTable(db); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const createMilestoneOptOutsTableQuery = `
+CREATE TABLE IF NOT EXISTS milestone_opt_outs (
+    discord_id TEXT PRIMARY KEY,
+    opted_out_at TEXT NOT NULL
+);
+`
+
+// CreateMilestoneOptOutsTable creates the milestone_opt_outs table if it does not exist.
+func CreateMilestoneOptOutsTable(db *sql.DB) error {
+	_, err := db.Exec(createMilestoneOptOutsTableQuery)
+	return err
+}
+
+// SetMilestoneOptOut turns a Discord user's milestone announcements off or back on.
+func SetMilestoneOptOut(db *sql.DB, discordID string, optOut bool) error {
+	if !optOut {
+		_, err := db.Exec(`DELETE FROM milestone_opt_outs WHERE discord_id = ?`, discordID)
+		return err
+	}
+	_, err := db.Exec(`
+		INSERT INTO milestone_opt_outs (discord_id, opted_out_at) VALUES (?, ?)
+		ON CONFLICT (discord_id) DO NOTHING
+	`, discordID, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// MilestoneOptedOut reports whether a Discord user turned off milestone announcements.
+func MilestoneOptedOut(db *sql.DB, discordID string) (bool, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM milestone_opt_outs WHERE discord_id = ?`, discordID).Scan(&n)
+	return n > 0, err
+}
@@ -0,0 +1,20 @@
+package model
+
+import "testing"
+
+func TestMilestoneOptOut(t *testing.T) {
+	db := newTestDB(t)
+
+	for _, step := range []struct {
+		optOut bool
+		want   bool
+	}{{true, true}, {true, true}, {false, false}} {
+		if err := SetMilestoneOptOut(db, "123", step.optOut); err != nil {
+			t.Fatal(err)
+		}
+		got, err := MilestoneOptedOut(db, "123")
+		if err != nil || got != step.want {
+			t.Errorf("after SetMilestoneOptOut(%v): opted out = %v, %v", step.optOut, got, err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AlertDirection tells whether an alert fires when the price drops below or rises above its threshold.
+type AlertDirection string
+
+const (
+	AlertBelow AlertDirection = "below"
+	AlertAbove AlertDirection = "above"
+)
+
+// PriceAlert is a user's subscription to an item's price crossing a threshold.
+type PriceAlert struct {
+	ID             int64          `json:"id"`
+	UserID         string         `json:"userId"`
+	ItemID         int            `json:"itemId"`
+	Direction      AlertDirection `json:"direction"`
+	Threshold      float64        `json:"threshold"`
+	Triggered      bool           `json:"triggered"` // set once notified, cleared when the price moves back
+	LastNotifiedAt time.Time      `json:"lastNotifiedAt"`
+}
+
+// Crossed reports whether price is on the alerting side of the threshold.
+func (a PriceAlert) Crossed(price float64) bool {
+	if a.Direction == AlertAbove {
+		return price >= a.Threshold
+	}
+	return price <= a.Threshold
+}
+
+const createPriceAlertsTableQuery = `
+CREATE TABLE IF NOT EXISTS price_alerts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL,
+    item_id INTEGER NOT NULL,
+    direction TEXT NOT NULL,
+    threshold REAL NOT NULL,
+    triggered INTEGER NOT NULL DEFAULT 0,
+    last_notified_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_alerts_user ON price_alerts (user_id);
+`
+
+// CreatePriceAlertsTable creates the price_alerts table if it does not exist.
+func CreatePriceAlertsTable(db *sql.DB) error {
+	_, err := db.Exec(createPriceAlertsTableQuery)
+	return err
+}
+
+// AddPriceAlert stores a new alert and returns its ID.
+func AddPriceAlert(db *sql.DB, userID string, itemID int, direction AlertDirection, threshold float64) (int64, error) {
+	res, err := db.Exec(`
+		INSERT INTO price_alerts (user_id, item_id, direction, threshold, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, userID, itemID, direction, threshold, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// CountPriceAlerts returns how many alerts a user has.
+func CountPriceAlerts(db *sql.DB, userID string) (int, error) {
+	var n int
+	err := db.QueryRow(`SELECT COUNT(*) FROM price_alerts WHERE user_id = ?`, userID).Scan(&n)
+	return n, err
+}
+
+// ListPriceAlerts returns a user's alerts, oldest first.
+func ListPriceAlerts(db *sql.DB, userID string) ([]PriceAlert, error) {
+	return queryPriceAlerts(db, `WHERE user_id = ? ORDER BY id ASC`, userID)
+}
+
+// GetAllPriceAlerts returns every alert of every user.
+func GetAllPriceAlerts(db *sql.DB) ([]PriceAlert, error) {
+	return queryPriceAlerts(db, `ORDER BY id ASC`)
+}
+
+// DeletePriceAlert removes one of the user's alerts. It reports whether an alert was removed.
+func DeletePriceAlert(db *sql.DB, userID string, id int64) (bool, error) {
+	res, err := db.Exec(`DELETE FROM price_alerts WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// MarkPriceAlertNotified records that the user was notified at the given time.
+func MarkPriceAlertNotified(db *sql.DB, id int64, at time.Time) error {
+	_, err := db.Exec(`UPDATE price_alerts SET triggered = 1, last_notified_at = ? WHERE id = ?`,
+		at.UTC().Format(time.RFC3339), id)
+	return err
+}
+
+// ResetPriceAlert re-arms an alert once the price is back on the quiet side of its threshold.
+func ResetPriceAlert(db *sql.DB, id int64) error {
+	_, err := db.Exec(`UPDATE price_alerts SET triggered = 0 WHERE id = ?`, id)
+	return err
+}
+
+func queryPriceAlerts(db *sql.DB, where string, args ...interface{}) ([]PriceAlert, error) {
+	rows, err := db.Query(`
+		SELECT id, user_id, item_id, direction, threshold, triggered, last_notified_at
+		FROM price_alerts `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []PriceAlert
+	for rows.Next() {
+		var a PriceAlert
+		var triggered int
+		var notified sql.NullString
+		if err := rows.Scan(&a.ID, &a.UserID, &a.ItemID, &a.Direction, &a.Threshold, &triggered, &notified); err != nil {
+			return nil, err
+		}
+		a.Triggered = triggered != 0
+		if notified.Valid {
+			if t, err := time.Parse(time.RFC3339, notified.String); err == nil {
+				a.LastNotifiedAt = t
+			}
+		}
+		results = append(results, a)
+	}
+	return results, rows.Err()
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const createRankRolesTableQuery = `
+CREATE TABLE IF NOT EXISTS rank_roles (
+    guild_id TEXT NOT NULL,
+    rank INTEGER NOT NULL,
+    role_id TEXT NOT NULL,
+    updated_at DATETIME,
+    PRIMARY KEY (guild_id, rank)
+);
+`
+
+// CreateRankRolesTable creates the rank_roles table if it does not exist.
+func CreateRankRolesTable(db *sql.DB) error {
+	_, err := db.Exec(createRankRolesTableQuery)
+	return err
+}
+
+// SetRankRole maps an in-game clan rank to a Discord role of the guild.
+func SetRankRole(db *sql.DB, guildID string, rank int, roleID string) error {
+	_, err := db.Exec(`
+		INSERT INTO rank_roles (guild_id, rank, role_id, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (guild_id, rank) DO UPDATE SET role_id = excluded.role_id, updated_at = excluded.updated_at
+	`, guildID, rank, roleID, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// DeleteRankRole removes a rank's role mapping. It reports whether a mapping was removed.
+func DeleteRankRole(db *sql.DB, guildID string, rank int) (bool, error) {
+	res, err := db.Exec(`DELETE FROM rank_roles WHERE guild_id = ? AND rank = ?`, guildID, rank)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListRankRoles returns a guild's role ID for each mapped rank.
+func ListRankRoles(db *sql.DB, guildID string) (map[int]string, error) {
+	rows, err := db.Query(`SELECT rank, role_id FROM rank_roles WHERE guild_id = ?`, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := make(map[int]string)
+	for rows.Next() {
+		var rank int
+		var roleID string
+		if err := rows.Scan(&rank, &roleID); err != nil {
+			return nil, err
+		}
+		roles[rank] = roleID
+	}
+	return roles, rows.Err()
+}
+
+// ListRankRoleGuilds returns the guilds with at least one rank mapped to a role.
+func ListRankRoleGuilds(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT DISTINCT guild_id FROM rank_roles ORDER BY guild_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var guilds []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		guilds = append(guilds, id)
+	}
+	return guilds, rows.Err()
+}
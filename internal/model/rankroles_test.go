@@ -0,0 +1,62 @@
+package model
+
+import "testing"
+
+func TestRankRoles(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := SetRankRole(db, "g1", 0, "member"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetRankRole(db, "g1", 1, "officer"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetRankRole(db, "g1", 1, "officer2"); err != nil {
+		t.Fatal(err)
+	}
+
+	roles, err := ListRankRoles(db, "g1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles) != 2 || roles[0] != "member" || roles[1] != "officer2" {
+		t.Errorf("roles = %v, want member and officer2", roles)
+	}
+	if guilds, _ := ListRankRoleGuilds(db); len(guilds) != 1 || guilds[0] != "g1" {
+		t.Errorf("guilds = %v, want [g1]", guilds)
+	}
+
+	if removed, err := DeleteRankRole(db, "g1", 0); err != nil || !removed {
+		t.Fatalf("DeleteRankRole = %v, %v", removed, err)
+	}
+	if roles, _ := ListRankRoles(db, "g1"); len(roles) != 1 {
+		t.Errorf("roles after delete = %v", roles)
+	}
+}
+
+func TestListLinkedMembers(t *testing.T) {
+	db := newTestDB(t)
+	guildan := MemberToDiscordID["guildan"]
+
+	if err := LinkMember(db, "newbie", "111"); err != nil {
+		t.Fatal(err)
+	}
+	// guildan relinks to an alt, replacing the static link
+	if err := LinkMember(db, "guildan_alt", guildan); err != nil {
+		t.Fatal(err)
+	}
+
+	linked, err := ListLinkedMembers(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linked["newbie"] != "111" || linked["guildan_alt"] != guildan {
+		t.Errorf("linked = %v", linked)
+	}
+	if _, ok := linked["guildan"]; ok {
+		t.Error("static link kept after the account relinked")
+	}
+	if linked["ImaKlutz"] != MemberToDiscordID["ImaKlutz"] {
+		t.Error("untouched static link missing")
+	}
+}
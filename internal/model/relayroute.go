@@ -0,0 +1,127 @@
+package model
+
+import (
+	"database/sql"
+	"regexp"
+	"time"
+)
+
+// EventCategory groups clan log lines for routing them to different channels.
+type EventCategory string
+
+const (
+	CategoryVault     EventCategory = "vault"     // items added to or taken from the clan vault
+	CategoryRoster    EventCategory = "roster"    // members joining, leaving or being kicked
+	CategoryPromotion EventCategory = "promotion" // rank changes
+	CategoryOther     EventCategory = "other"     // everything else
+)
+
+// EventCategories lists every category in display order.
+var EventCategories = []EventCategory{CategoryVault, CategoryRoster, CategoryPromotion, CategoryOther}
+
+var (
+	promotionLineRe = regexp.MustCompile(`(?i)\b(promoted|demoted)\b`)
+	rosterLineRe    = regexp.MustCompile(`(?i)\b(joined|left)\s+the\s+clan\b|\b(kicked|invited)\b`)
+)
+
+// ClassifyClanMessage returns the routing category of a clan log line.
+func ClassifyClanMessage(message string) EventCategory {
+	switch {
+	case vaultMovementRe.MatchString(message):
+		return CategoryVault
+	case promotionLineRe.MatchString(message):
+		return CategoryPromotion
+	case rosterLineRe.MatchString(message):
+		return CategoryRoster
+	}
+	return CategoryOther
+}
+
+// ParseEventCategory returns the category with the given name.
+func ParseEventCategory(name string) (EventCategory, bool) {
+	for _, c := range EventCategories {
+		if string(c) == name {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// RelayRoute overrides where a guild's clan log lines of one category are posted.
+// A route either names a channel or drops the category; categories without a route
+// go to the default relay channel.
+type RelayRoute struct {
+	GuildID   string        `json:"guildId"`
+	Category  EventCategory `json:"category"`
+	ChannelID string        `json:"channelId"`
+	Drop      bool          `json:"drop"`
+}
+
+const createRelayRoutesTableQuery = `
+CREATE TABLE IF NOT EXISTS relay_routes (
+    guild_id TEXT NOT NULL,
+    category TEXT NOT NULL,
+    channel_id TEXT NOT NULL DEFAULT '',
+    drop_lines INTEGER NOT NULL DEFAULT 0,
+    updated_at DATETIME,
+    PRIMARY KEY (guild_id, category)
+);
+`
+
+// CreateRelayRoutesTable creates the relay_routes table if it does not exist.
+func CreateRelayRoutesTable(db *sql.DB) error {
+	_, err := db.Exec(createRelayRoutesTableQuery)
+	return err
+}
+
+// SetRelayRoute creates or replaces the route of a guild's category.
+func SetRelayRoute(db *sql.DB, r RelayRoute) error {
+	drop := 0
+	if r.Drop {
+		drop = 1
+	}
+	_, err := db.Exec(`
+		INSERT INTO relay_routes (guild_id, category, channel_id, drop_lines, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (guild_id, category) DO UPDATE SET
+			channel_id = excluded.channel_id,
+			drop_lines = excluded.drop_lines,
+			updated_at = excluded.updated_at
+	`, r.GuildID, r.Category, r.ChannelID, drop, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// DeleteRelayRoute sends a guild's category back to the default relay channel.
+// It reports whether a route was removed.
+func DeleteRelayRoute(db *sql.DB, guildID string, category EventCategory) (bool, error) {
+	res, err := db.Exec(`DELETE FROM relay_routes WHERE guild_id = ? AND category = ?`, guildID, category)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListRelayRoutes returns a guild's routes keyed by category.
+func ListRelayRoutes(db *sql.DB, guildID string) (map[EventCategory]RelayRoute, error) {
+	rows, err := db.Query(`
+		SELECT guild_id, category, channel_id, drop_lines
+		FROM relay_routes WHERE guild_id = ?
+	`, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	routes := make(map[EventCategory]RelayRoute)
+	for rows.Next() {
+		var r RelayRoute
+		var drop int
+		if err := rows.Scan(&r.GuildID, &r.Category, &r.ChannelID, &drop); err != nil {
+			return nil, err
+		}
+		r.Drop = drop != 0
+		routes[r.Category] = r
+	}
+	return routes, rows.Err()
+}
@@ -0,0 +1,67 @@
+package model
+
+import "testing"
+
+func TestClassifyClanMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		want    EventCategory
+	}{
+		{"guildan added 1000000x Gold.", CategoryVault},
+		{"ImaKlutz withdrew 2x Stone key.", CategoryVault},
+		{"moraxam joined the clan.", CategoryRoster},
+		{"x left the clan.", CategoryRoster},
+		{"y was kicked from the clan by z.", CategoryRoster},
+		{"guildan promoted moraxam to Officer.", CategoryPromotion},
+		{"moraxam was demoted to Member.", CategoryPromotion},
+		{"The clan upgraded its house.", CategoryOther},
+	}
+	for _, tt := range tests {
+		if got := ClassifyClanMessage(tt.message); got != tt.want {
+			t.Errorf("ClassifyClanMessage(%q) = %s, want %s", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestRelayRoutes(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := SetRelayRoute(db, RelayRoute{GuildID: "g1", Category: CategoryVault, ChannelID: "vault"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetRelayRoute(db, RelayRoute{GuildID: "g1", Category: CategoryOther, Drop: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetRelayRoute(db, RelayRoute{GuildID: "g2", Category: CategoryVault, ChannelID: "elsewhere"}); err != nil {
+		t.Fatal(err)
+	}
+	// replacing a route keeps one row per category
+	if err := SetRelayRoute(db, RelayRoute{GuildID: "g1", Category: CategoryVault, ChannelID: "vault2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := ListRelayRoutes(db, "g1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("got %d routes for g1, want 2", len(routes))
+	}
+	if r := routes[CategoryVault]; r.ChannelID != "vault2" || r.Drop {
+		t.Errorf("vault route = %+v, want channel vault2", r)
+	}
+	if r := routes[CategoryOther]; !r.Drop {
+		t.Errorf("other route = %+v, want dropped", r)
+	}
+
+	removed, err := DeleteRelayRoute(db, "g1", CategoryVault)
+	if err != nil || !removed {
+		t.Fatalf("DeleteRelayRoute = %v, %v; want true", removed, err)
+	}
+	if removed, _ := DeleteRelayRoute(db, "g1", CategoryVault); removed {
+		t.Error("deleting a missing route reported a removal")
+	}
+	if routes, _ := ListRelayRoutes(db, "g2"); routes[CategoryVault].ChannelID != "elsewhere" {
+		t.Errorf("other guild's route changed: %+v", routes)
+	}
+}
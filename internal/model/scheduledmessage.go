@@ -12,6 +12,7 @@ const (
 	MessageTypeDaily       MessageType = "daily"
 	MessageTypeWeekly      MessageType = "weekly"
 	MessageTypeBossSummary MessageType = "bosssummary"
+	MessageTypeDigest      MessageType = "digest"
 )
 
 // ScheduledMessage represents a scheduled message stored in the database
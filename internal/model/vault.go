@@ -0,0 +1,129 @@
+package model
+
+import (
+	"database/sql"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// vaultMovementRe matches "player added 3x Godly key.", "player withdrew 1x Gold."
+// and "player took 2x Stone key."
+var vaultMovementRe = regexp.MustCompile(`^(.+?)\s+(added|withdrew|took)\s+(\d+)x\s+(.+?)\.$`)
+
+// VaultMovement is an item moving between a member and the clan vault.
+type VaultMovement struct {
+	Player  string
+	Item    string // as written in the clan log, e.g. "Gold" or "Godly key"
+	Count   int64
+	Deposit bool
+}
+
+// ParseVaultMovement extracts a vault deposit or withdrawal from a clan log line.
+func ParseVaultMovement(message string) (VaultMovement, bool) {
+	matches := vaultMovementRe.FindStringSubmatch(message)
+	if len(matches) != 5 {
+		return VaultMovement{}, false
+	}
+	count, err := strconv.ParseInt(matches[3], 10, 64)
+	if err != nil || count <= 0 {
+		return VaultMovement{}, false
+	}
+	return VaultMovement{
+		Player:  matches[1],
+		Item:    strings.TrimSpace(matches[4]),
+		Count:   count,
+		Deposit: matches[2] == "added",
+	}, true
+}
+
+// IsGold reports whether the movement is gold rather than an item.
+func (v VaultMovement) IsGold() bool {
+	return strings.EqualFold(v.Item, "gold")
+}
+
+// VaultContribution is what one member put into the vault.
+type VaultContribution struct {
+	Player   string
+	Gold     int64
+	Items    int64 // non-gold items deposited
+	Deposits int
+}
+
+// VaultSummary totals the vault movements of a period.
+type VaultSummary struct {
+	GoldDeposited  int64
+	GoldWithdrawn  int64
+	ItemsDeposited int64
+	ItemDeposits   map[string]int64    // non-gold items deposited, by item
+	Contributors   []VaultContribution // most gold first, then most items
+}
+
+// SummarizeVault totals the vault movements logged since the given time.
+func SummarizeVault(db *sql.DB, since time.Time) (VaultSummary, error) {
+	return SummarizeVaultBetween(db, since, time.Now())
+}
+
+// SummarizeVaultBetween totals the vault movements logged in [since, until).
+func SummarizeVaultBetween(db *sql.DB, since, until time.Time) (VaultSummary, error) {
+	rows, err := db.Query(`SELECT message FROM clan_messages WHERE timestamp >= ? AND timestamp < ?`,
+		since.UTC().Format(time.RFC3339), until.UTC().Format(time.RFC3339))
+	if err != nil {
+		return VaultSummary{}, err
+	}
+	defer rows.Close()
+
+	summary := VaultSummary{ItemDeposits: make(map[string]int64)}
+	byPlayer := make(map[string]*VaultContribution)
+	for rows.Next() {
+		var message string
+		if err := rows.Scan(&message); err != nil {
+			return VaultSummary{}, err
+		}
+		mv, ok := ParseVaultMovement(message)
+		if !ok {
+			continue
+		}
+		if !mv.Deposit {
+			if mv.IsGold() {
+				summary.GoldWithdrawn += mv.Count
+			}
+			continue
+		}
+
+		c, ok := byPlayer[mv.Player]
+		if !ok {
+			c = &VaultContribution{Player: mv.Player}
+			byPlayer[mv.Player] = c
+		}
+		c.Deposits++
+		if mv.IsGold() {
+			c.Gold += mv.Count
+			summary.GoldDeposited += mv.Count
+		} else {
+			c.Items += mv.Count
+			summary.ItemsDeposited += mv.Count
+			summary.ItemDeposits[mv.Item] += mv.Count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return VaultSummary{}, err
+	}
+
+	for _, c := range byPlayer {
+		summary.Contributors = append(summary.Contributors, *c)
+	}
+	sort.Slice(summary.Contributors, func(i, j int) bool {
+		a, b := summary.Contributors[i], summary.Contributors[j]
+		if a.Gold != b.Gold {
+			return a.Gold > b.Gold
+		}
+		if a.Items != b.Items {
+			return a.Items > b.Items
+		}
+		return a.Player < b.Player
+	})
+	return summary, nil
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizeVault(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)
+
+	for i, text := range []string{
+		"guildan added 1000000x Gold.",
+		"guildan added 2x Godly key.",
+		"moraxam added 5000x Gold.",
+		"yothos added 10x Krono's book.",
+		"moraxam withdrew 300x Gold.",
+		"x joined the clan.",
+	} {
+		insertTestMessage(t, db, text, now.Add(-time.Duration(i)*time.Hour))
+	}
+	insertTestMessage(t, db, "old added 9999999x Gold.", now.AddDate(0, 0, -10))
+
+	summary, err := SummarizeVault(db, now.AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.GoldDeposited != 1005000 || summary.GoldWithdrawn != 300 || summary.ItemsDeposited != 12 {
+		t.Errorf("totals = %+v", summary)
+	}
+
+	want := []VaultContribution{
+		{Player: "guildan", Gold: 1000000, Items: 2, Deposits: 2},
+		{Player: "moraxam", Gold: 5000, Deposits: 1},
+		{Player: "yothos", Items: 10, Deposits: 1},
+	}
+	if len(summary.Contributors) != len(want) {
+		t.Fatalf("contributors = %+v", summary.Contributors)
+	}
+	for i, c := range summary.Contributors {
+		if c != want[i] {
+			t.Errorf("contributor %d = %+v, want %+v", i, c, want[i])
+		}
+	}
+	if summary.ItemDeposits["Godly key"] != 2 || summary.ItemDeposits["Krono's book"] != 10 || len(summary.ItemDeposits) != 2 {
+		t.Errorf("item deposits = %v", summary.ItemDeposits)
+	}
+
+	// only the lines logged three or more hours before now
+	earlier, err := SummarizeVaultBetween(db, now.AddDate(0, 0, -7), now.Add(-3*time.Hour+time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if earlier.GoldDeposited != 0 || earlier.GoldWithdrawn != 300 || earlier.ItemsDeposited != 10 {
+		t.Errorf("bounded totals = %+v", earlier)
+	}
+}
+
+func TestParseVaultMovement(t *testing.T) {
+	mv, ok := ParseVaultMovement("yothos took 1x Krono's book.")
+	if !ok || mv.Player != "yothos" || mv.Item != "Krono's book" || mv.Count != 1 || mv.Deposit || mv.IsGold() {
+		t.Errorf("ParseVaultMovement = %+v, %v", mv, ok)
+	}
+	if _, ok := ParseVaultMovement("x joined the clan."); ok {
+		t.Error("roster line parsed as a vault movement")
+	}
+}
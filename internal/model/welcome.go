@@ -0,0 +1,92 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// WelcomeSettings configures a guild's welcome and farewell posts for clan joins and leaves.
+type WelcomeSettings struct {
+	GuildID         string `json:"guildId"`
+	ChannelID       string `json:"channelId"`
+	RoleID          string `json:"roleId,omitempty"`          // given to linked members while they are in the clan
+	WelcomeMessage  string `json:"welcomeMessage,omitempty"`  // "" uses the default
+	FarewellMessage string `json:"farewellMessage,omitempty"` // "" uses the default
+}
+
+const createWelcomeSettingsTableQuery = `
+CREATE TABLE IF NOT EXISTS welcome_settings (
+    guild_id TEXT PRIMARY KEY,
+    channel_id TEXT NOT NULL,
+    role_id TEXT NOT NULL DEFAULT '',
+    welcome_message TEXT NOT NULL DEFAULT '',
+    farewell_message TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME
+);
+`
+
+// CreateWelcomeSettingsTable creates the welcome_settings table if it does not exist.
+func CreateWelcomeSettingsTable(db *sql.DB) error {
+	_, err := db.Exec(createWelcomeSettingsTableQuery)
+	return err
+}
+
+// SetWelcomeSettings creates or replaces a guild's welcome settings.
+func SetWelcomeSettings(db *sql.DB, s WelcomeSettings) error {
+	_, err := db.Exec(`
+		INSERT INTO welcome_settings (guild_id, channel_id, role_id, welcome_message, farewell_message, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (guild_id) DO UPDATE SET
+			channel_id = excluded.channel_id,
+			role_id = excluded.role_id,
+			welcome_message = excluded.welcome_message,
+			farewell_message = excluded.farewell_message,
+			updated_at = excluded.updated_at
+	`, s.GuildID, s.ChannelID, s.RoleID, s.WelcomeMessage, s.FarewellMessage, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// DeleteWelcomeSettings turns off the welcome workflow of a guild.
+// It reports whether settings were removed.
+func DeleteWelcomeSettings(db *sql.DB, guildID string) (bool, error) {
+	res, err := db.Exec(`DELETE FROM welcome_settings WHERE guild_id = ?`, guildID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetWelcomeSettings returns a guild's welcome settings, if the workflow is enabled there.
+func GetWelcomeSettings(db *sql.DB, guildID string) (WelcomeSettings, bool, error) {
+	settings, err := queryWelcomeSettings(db, `WHERE guild_id = ?`, guildID)
+	if err != nil || len(settings) == 0 {
+		return WelcomeSettings{}, false, err
+	}
+	return settings[0], true, nil
+}
+
+// ListWelcomeSettings returns the settings of every guild with the workflow enabled.
+func ListWelcomeSettings(db *sql.DB) ([]WelcomeSettings, error) {
+	return queryWelcomeSettings(db, `ORDER BY guild_id`)
+}
+
+func queryWelcomeSettings(db *sql.DB, where string, args ...interface{}) ([]WelcomeSettings, error) {
+	rows, err := db.Query(`
+		SELECT guild_id, channel_id, role_id, welcome_message, farewell_message
+		FROM welcome_settings `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []WelcomeSettings
+	for rows.Next() {
+		var s WelcomeSettings
+		if err := rows.Scan(&s.GuildID, &s.ChannelID, &s.RoleID, &s.WelcomeMessage, &s.FarewellMessage); err != nil {
+			return nil, err
+		}
+		results = append(results, s)
+	}
+	return results, rows.Err()
+}
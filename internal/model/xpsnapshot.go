@@ -0,0 +1,188 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// xpSnapshotDay is the layout of xp_snapshots.taken_on.
+const xpSnapshotDay = "2006-01-02"
+
+// XPGain is how much experience a player gained over a period.
+type XPGain struct {
+	GameName string
+	Gain     float64
+	From     time.Time // day of the baseline snapshot
+	To       time.Time // day of the latest snapshot
+}
+
+// XPPoint is a player's experience on one day.
+type XPPoint struct {
+	Day time.Time
+	XP  float64
+}
+
+const createXPSnapshotsTableQuery = `
+CREATE TABLE IF NOT EXISTS xp_snapshots (
+    game_name TEXT NOT NULL,
+    skill TEXT NOT NULL,
+    taken_on TEXT NOT NULL,
+    xp REAL NOT NULL,
+    PRIMARY KEY (game_name, skill, taken_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_xp_snapshots_taken_on ON xp_snapshots (taken_on);
+`
+
+// CreateXPSnapshotsTable creates the xp_snapshots table if it does not exist.
+func CreateXPSnapshotsTable(db *sql.DB) error {
+	_, err := db.Exec(createXPSnapshotsTableQuery)
+	return err
+}
+
+// SaveXPSnapshot stores a player's per-skill experience for a day, replacing an earlier snapshot of that day.
+func SaveXPSnapshot(db *sql.DB, gameName string, day time.Time, xp map[string]float64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO xp_snapshots (game_name, skill, taken_on, xp) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	taken := day.Format(xpSnapshotDay)
+	for skill, v := range xp {
+		if _, err := stmt.Exec(gameName, skill, taken, v); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// LatestXPSnapshot returns a player's most recent per-skill experience and its day,
+// or a nil map if the player has no snapshot yet.
+func LatestXPSnapshot(db *sql.DB, gameName string) (map[string]float64, time.Time, error) {
+	rows, err := db.Query(`
+		SELECT skill, xp, taken_on
+		FROM xp_snapshots
+		WHERE game_name = ? AND taken_on = (SELECT MAX(taken_on) FROM xp_snapshots WHERE game_name = ?)
+	`, gameName, gameName)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer rows.Close()
+
+	var xp map[string]float64
+	var day time.Time
+	for rows.Next() {
+		var skill, taken string
+		var v float64
+		if err := rows.Scan(&skill, &v, &taken); err != nil {
+			return nil, time.Time{}, err
+		}
+		if xp == nil {
+			xp = make(map[string]float64)
+			day, _ = time.Parse(xpSnapshotDay, taken)
+		}
+		xp[skill] = v
+	}
+	return xp, day, rows.Err()
+}
+
+// XPGains ranks players by the experience gained between their first snapshot on or after since
+// and their latest one, in one skill or, when skill is "", in all skills together. Only skills
+// present in both snapshots count, so a skill first seen in the latest one adds no gain.
+func XPGains(db *sql.DB, skill string, since time.Time) ([]XPGain, error) {
+	rows, err := db.Query(`
+		WITH bounds AS (
+			SELECT game_name, MIN(taken_on) AS first_day, MAX(taken_on) AS last_day
+			FROM xp_snapshots
+			WHERE taken_on >= ?
+			GROUP BY game_name
+		)
+		SELECT b.game_name, b.first_day, b.last_day, SUM(l.xp - f.xp) AS gain
+		FROM bounds b
+		JOIN xp_snapshots f ON f.game_name = b.game_name AND f.taken_on = b.first_day
+		JOIN xp_snapshots l ON l.game_name = b.game_name AND l.taken_on = b.last_day AND l.skill = f.skill
+		WHERE ? = '' OR f.skill = ?
+		GROUP BY b.game_name
+		ORDER BY gain DESC, b.game_name
+	`, since.Format(xpSnapshotDay), skill, skill)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var gains []XPGain
+	for rows.Next() {
+		var g XPGain
+		var from, to string
+		if err := rows.Scan(&g.GameName, &from, &to, &g.Gain); err != nil {
+			return nil, err
+		}
+		g.From, _ = time.Parse(xpSnapshotDay, from)
+		g.To, _ = time.Parse(xpSnapshotDay, to)
+		gains = append(gains, g)
+	}
+	return gains, rows.Err()
+}
+
+// XPHistory returns a player's daily experience since the given day, in one skill or,
+// when skill is "", in the skills of their first snapshot in the range together, oldest first.
+// The player's name is matched case-insensitively.
+func XPHistory(db *sql.DB, gameName, skill string, since time.Time) ([]XPPoint, error) {
+	day := since.Format(xpSnapshotDay)
+	rows, err := db.Query(`
+		WITH first AS (
+			SELECT skill FROM xp_snapshots
+			WHERE game_name = ? COLLATE NOCASE AND taken_on = (
+				SELECT MIN(taken_on) FROM xp_snapshots WHERE game_name = ? COLLATE NOCASE AND taken_on >= ?
+			)
+		)
+		SELECT taken_on, SUM(xp)
+		FROM xp_snapshots
+		WHERE game_name = ? COLLATE NOCASE AND taken_on >= ? AND skill IN (SELECT skill FROM first)
+			AND (? = '' OR skill = ?)
+		GROUP BY taken_on
+		ORDER BY taken_on
+	`, gameName, gameName, day, gameName, day, skill, skill)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []XPPoint
+	for rows.Next() {
+		var day string
+		var p XPPoint
+		if err := rows.Scan(&day, &p.XP); err != nil {
+			return nil, err
+		}
+		p.Day, _ = time.Parse(xpSnapshotDay, day)
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// ListXPSkills returns every skill with a snapshot, alphabetically.
+func ListXPSkills(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT DISTINCT skill FROM xp_snapshots ORDER BY skill`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var skills []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		skills = append(skills, s)
+	}
+	return skills, rows.Err()
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestXPGainsAndHistory(t *testing.T) {
+	db := newTestDB(t)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	snapshots := []struct {
+		player string
+		day    int
+		xp     map[string]float64
+	}{
+		{"alice", 1, map[string]float64{"attack": 1000, "mining": 500}},
+		{"alice", 2, map[string]float64{"attack": 1500, "mining": 500}},
+		{"alice", 8, map[string]float64{"attack": 4000, "mining": 900}},
+		{"bob", 2, map[string]float64{"attack": 100, "mining": 100}},
+		{"bob", 8, map[string]float64{"attack": 200, "mining": 5100}},
+	}
+	for _, s := range snapshots {
+		if err := SaveXPSnapshot(db, s.player, day(s.day), s.xp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a second snapshot on the same day replaces the first
+	if err := SaveXPSnapshot(db, "bob", day(8), map[string]float64{"attack": 300, "mining": 5100}); err != nil {
+		t.Fatal(err)
+	}
+
+	// since day 2: alice 1500+500 -> 4000+900, bob 100+100 -> 300+5100
+	gains, err := XPGains(db, "", day(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gains) != 2 || gains[0].GameName != "bob" || gains[0].Gain != 5200 || gains[1].Gain != 2900 {
+		t.Errorf("total gains = %+v", gains)
+	}
+	if !gains[0].From.Equal(day(2)) || !gains[0].To.Equal(day(8)) {
+		t.Errorf("bob's period = %s..%s", gains[0].From, gains[0].To)
+	}
+
+	gains, err = XPGains(db, "attack", day(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gains) != 2 || gains[0].GameName != "alice" || gains[0].Gain != 3000 || gains[1].Gain != 200 {
+		t.Errorf("attack gains = %+v", gains)
+	}
+
+	history, err := XPHistory(db, "alice", "", day(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 || history[0].XP != 1500 || history[2].XP != 4900 {
+		t.Errorf("history = %+v", history)
+	}
+
+	skills, err := ListXPSkills(db)
+	if err != nil || len(skills) != 2 || skills[0] != "attack" {
+		t.Errorf("skills = %v, %v", skills, err)
+	}
+
+	latest, latestDay, err := LatestXPSnapshot(db, "bob")
+	if err != nil || !latestDay.Equal(day(8)) || latest["attack"] != 300 || latest["mining"] != 5100 {
+		t.Errorf("latest = %v on %s, %v", latest, latestDay, err)
+	}
+	if none, _, err := LatestXPSnapshot(db, "carol"); err != nil || none != nil {
+		t.Errorf("latest of unknown player = %v, %v", none, err)
+	}
+}
+
+func TestXPGainsIgnoreNewSkills(t *testing.T) {
+	db := newTestDB(t)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	if err := SaveXPSnapshot(db, "Carol", day(2), map[string]float64{"attack": 100}); err != nil {
+		t.Fatal(err)
+	}
+	// farming shows up for the first time, with all of its experience
+	if err := SaveXPSnapshot(db, "Carol", day(8), map[string]float64{"attack": 250, "farming": 9000}); err != nil {
+		t.Fatal(err)
+	}
+
+	gains, err := XPGains(db, "", day(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gains) != 1 || gains[0].Gain != 150 {
+		t.Errorf("total gains = %+v, want 150", gains)
+	}
+	gains, err = XPGains(db, "farming", day(1))
+	if err != nil || len(gains) != 0 {
+		t.Errorf("farming gains = %+v, %v, want none", gains, err)
+	}
+
+	history, err := XPHistory(db, "carol", "", day(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].XP != 100 || history[1].XP != 250 {
+		t.Errorf("history = %+v", history)
+	}
+}
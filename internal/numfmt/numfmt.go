@@ -0,0 +1,23 @@
+// Package numfmt formats numbers for display in Discord messages.
+package numfmt
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Thousands formats a number with comma separators (e.g., 1000000 -> "1,000,000").
+func Thousands(n int64) string {
+	if n < 0 {
+		return "-" + Thousands(-n)
+	}
+	s := strconv.FormatInt(n, 10)
+	var sb strings.Builder
+	for i, digit := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(digit)
+	}
+	return sb.String()
+}
@@ -0,0 +1,250 @@
+// Package ranksync mirrors in-game clan ranks onto Discord roles for linked members.
+package ranksync
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"klutco-lil-helper/internal/idleclans"
+	"klutco-lil-helper/internal/logging"
+	"klutco-lil-helper/internal/model"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Change is one role to add to or remove from a member.
+type Change struct {
+	GameName string
+	UserID   string
+	RoleID   string
+	Rank     idleclans.Rank // the member's current rank; meaningless when InClan is false
+	InClan   bool
+	Add      bool
+}
+
+func (c Change) String() string {
+	verb, prep := "add", "to"
+	if !c.Add {
+		verb, prep = "remove", "from"
+	}
+	reason := "left the clan"
+	if c.InClan {
+		reason = c.Rank.String()
+	}
+	return fmt.Sprintf("%s <@&%s> %s %s (<@%s>, %s)", verb, c.RoleID, prep, c.GameName, c.UserID, reason)
+}
+
+// Report is the outcome of one sync of a guild.
+type Report struct {
+	GuildID    string
+	DryRun     bool
+	Changes    []Change
+	Failed     []Change // changes Discord rejected; always empty on dry runs
+	NotInGuild []string // linked game names whose account is not a member of the guild
+}
+
+// Plan works out which mapped roles to add and remove so that every linked member holds
+// exactly the role of their rank. Linked members who are not in the clan lose all mapped roles.
+// current holds the roles of each linked account found in the guild; others are left alone.
+func Plan(clan *idleclans.Clan, linked map[string]string, rankRoles map[int]string, current map[string][]string) []Change {
+	ranks := make(map[string]idleclans.Rank, len(clan.Members))
+	for _, m := range clan.Members {
+		ranks[strings.ToLower(m.Name)] = m.Rank
+	}
+
+	gameNames := make([]string, 0, len(linked))
+	for name := range linked {
+		gameNames = append(gameNames, name)
+	}
+	sort.Strings(gameNames)
+
+	ranksMapped := make([]int, 0, len(rankRoles))
+	for rank := range rankRoles {
+		ranksMapped = append(ranksMapped, rank)
+	}
+	sort.Ints(ranksMapped)
+
+	// several ranks may share a role; each role is added or removed once
+	var mapped []string
+	seen := make(map[string]bool, len(rankRoles))
+	for _, rank := range ranksMapped {
+		if roleID := rankRoles[rank]; !seen[roleID] {
+			seen[roleID] = true
+			mapped = append(mapped, roleID)
+		}
+	}
+
+	var changes []Change
+	for _, name := range gameNames {
+		userID := linked[name]
+		roles, ok := current[userID]
+		if !ok {
+			continue
+		}
+		has := make(map[string]bool, len(roles))
+		for _, r := range roles {
+			has[r] = true
+		}
+
+		rank, inClan := ranks[strings.ToLower(name)]
+		want := ""
+		if inClan {
+			want = rankRoles[int(rank)]
+		}
+
+		for _, roleID := range mapped {
+			c := Change{GameName: name, UserID: userID, RoleID: roleID, Rank: rank, InClan: inClan}
+			switch {
+			case roleID == want && !has[roleID]:
+				c.Add = true
+				changes = append(changes, c)
+			case roleID != want && has[roleID]:
+				changes = append(changes, c)
+			}
+		}
+	}
+	return changes
+}
+
+// Sync reads the clan's ranks and brings the guild's mapped roles in line with them.
+// With dryRun, the changes are only reported.
+func Sync(ctx context.Context, s *discordgo.Session, db *sql.DB, client *idleclans.Client, clanName, guildID string, dryRun bool, logger *slog.Logger) (Report, error) {
+	if logger == nil {
+		logger = logging.Discard()
+	}
+	logger = logger.With(logging.KeyGuild, guildID, "dry_run", dryRun)
+	report := Report{GuildID: guildID, DryRun: dryRun}
+
+	rankRoles, err := model.ListRankRoles(db, guildID)
+	if err != nil {
+		return report, fmt.Errorf("load rank roles: %w", err)
+	}
+	if len(rankRoles) == 0 {
+		return report, nil
+	}
+
+	clan, err := client.ClanInfo(ctx, clanName)
+	if err != nil {
+		return report, fmt.Errorf("fetch clan info: %w", err)
+	}
+
+	linked, err := model.ListLinkedMembers(db)
+	if err != nil {
+		return report, fmt.Errorf("load linked members: %w", err)
+	}
+
+	current := make(map[string][]string, len(linked))
+	for name, userID := range linked {
+		member, err := s.GuildMember(guildID, userID)
+		if err != nil {
+			var restErr *discordgo.RESTError
+			if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound {
+				report.NotInGuild = append(report.NotInGuild, name)
+				continue
+			}
+			return report, fmt.Errorf("get guild member %s: %w", userID, err)
+		}
+		current[userID] = member.Roles
+	}
+	sort.Strings(report.NotInGuild)
+
+	report.Changes = Plan(clan, linked, rankRoles, current)
+	if dryRun {
+		for _, c := range report.Changes {
+			logger.Info("would change rank role", "change", c.String())
+		}
+		return report, nil
+	}
+
+	for _, c := range report.Changes {
+		if c.Add {
+			err = s.GuildMemberRoleAdd(guildID, c.UserID, c.RoleID)
+		} else {
+			err = s.GuildMemberRoleRemove(guildID, c.UserID, c.RoleID)
+		}
+		if err != nil {
+			logger.Warn("failed to change rank role", "change", c.String(), logging.Err(err))
+			report.Failed = append(report.Failed, c)
+			continue
+		}
+		logger.Info("changed rank role", "change", c.String())
+	}
+	return report, nil
+}
+
+const (
+	// maxReportLen is Discord's limit on the content of a message.
+	maxReportLen = 2000
+	// maxNotInGuildLen bounds the list of linked members missing from the guild.
+	maxNotInGuildLen = 300
+)
+
+// FormatReport describes a sync for Discord, listing as many changes as fit in one message.
+func FormatReport(r Report) string {
+	var header string
+	switch {
+	case len(r.Changes) == 0:
+		header = "✅ Every linked member already has the role of their rank."
+	case r.DryRun:
+		header = fmt.Sprintf("🔍 Dry run: %d role change(s) would be made.", len(r.Changes))
+	default:
+		header = fmt.Sprintf("🔁 Made %d of %d role change(s).", len(r.Changes)-len(r.Failed), len(r.Changes))
+	}
+
+	failed := make(map[Change]bool, len(r.Failed))
+	for _, c := range r.Failed {
+		failed[c] = true
+	}
+	// failed changes first, so the ones that need attention are never cut off
+	lines := make([]string, 0, len(r.Changes))
+	for _, c := range r.Changes {
+		if failed[c] {
+			lines = append(lines, "• "+c.String()+" ❌")
+		}
+	}
+	for _, c := range r.Changes {
+		if !failed[c] {
+			lines = append(lines, "• "+c.String())
+		}
+	}
+
+	notInGuild := ""
+	if len(r.NotInGuild) > 0 {
+		notInGuild = "\nNot in this server: " + joinCapped(r.NotInGuild, ", ", maxNotInGuildLen)
+	}
+	if len(lines) > 0 {
+		header += "\n" + joinCapped(lines, "\n", maxReportLen-len(header)-len(notInGuild)-1)
+	}
+	return header + notInGuild
+}
+
+// joinCapped joins items with sep into at most limit bytes, ending with "…and N more"
+// when some items don't fit.
+func joinCapped(items []string, sep string, limit int) string {
+	var sb strings.Builder
+	for i, item := range items {
+		addition := item
+		if i > 0 {
+			addition = sep + item
+		}
+		reserve := 0
+		if i < len(items)-1 {
+			reserve = len(fmt.Sprintf("%s…and %d more", sep, len(items)-i-1))
+		}
+		if sb.Len()+len(addition)+reserve > limit {
+			if i > 0 {
+				sb.WriteString(sep)
+			}
+			fmt.Fprintf(&sb, "…and %d more", len(items)-i)
+			break
+		}
+		sb.WriteString(addition)
+	}
+	return sb.String()
+}
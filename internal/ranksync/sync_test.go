@@ -0,0 +1,119 @@
+package ranksync
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"klutco-lil-helper/internal/idleclans"
+)
+
+func TestPlan(t *testing.T) {
+	clan := &idleclans.Clan{Members: []idleclans.ClanMember{
+		{Name: "ImaKlutz", Rank: idleclans.RankLeader},
+		{Name: "guildan", Rank: idleclans.RankOfficer},
+		{Name: "moraxam", Rank: idleclans.RankMember},
+	}}
+	linked := map[string]string{
+		"ImaKlutz": "1",
+		"guildan":  "2",
+		"moraxam":  "3",
+		"leaver":   "4",
+		"stranger": "5", // not in the guild
+	}
+	rankRoles := map[int]string{
+		int(idleclans.RankMember):  "member-role",
+		int(idleclans.RankOfficer): "officer-role",
+	}
+	current := map[string][]string{
+		"1": {"member-role", "unrelated"}, // leader rank has no role: drop the stale one
+		"2": {"member-role"},              // promoted: swap roles
+		"3": {"member-role"},              // already right
+		"4": {"officer-role", "unrelated"},
+	}
+
+	changes := Plan(clan, linked, rankRoles, current)
+
+	var got []string
+	for _, c := range changes {
+		op := "-"
+		if c.Add {
+			op = "+"
+		}
+		got = append(got, op+c.GameName+":"+c.RoleID)
+	}
+	want := "-ImaKlutz:member-role -guildan:member-role +guildan:officer-role -leaver:officer-role"
+	if strings.Join(got, " ") != want {
+		t.Errorf("changes = %s\nwant      %s", strings.Join(got, " "), want)
+	}
+	if changes[3].InClan {
+		t.Error("leaver's change marked as in the clan")
+	}
+}
+
+func TestPlanSharedRole(t *testing.T) {
+	clan := &idleclans.Clan{Members: []idleclans.ClanMember{
+		{Name: "guildan", Rank: idleclans.RankOfficer},
+		{Name: "ImaKlutz", Rank: idleclans.RankLeader},
+	}}
+	linked := map[string]string{"guildan": "2", "ImaKlutz": "1"}
+	rankRoles := map[int]string{
+		int(idleclans.RankMember):  "member-role",
+		int(idleclans.RankOfficer): "staff-role",
+		int(idleclans.RankLeader):  "staff-role",
+	}
+	current := map[string][]string{"2": {}, "1": {"member-role"}}
+
+	var got []string
+	for _, c := range Plan(clan, linked, rankRoles, current) {
+		op := "-"
+		if c.Add {
+			op = "+"
+		}
+		got = append(got, op+c.GameName+":"+c.RoleID)
+	}
+	want := "-ImaKlutz:member-role +ImaKlutz:staff-role +guildan:staff-role"
+	if strings.Join(got, " ") != want {
+		t.Errorf("changes = %s\nwant      %s", strings.Join(got, " "), want)
+	}
+}
+
+func TestFormatReportFitsInOneMessage(t *testing.T) {
+	var r Report
+	for i := 0; i < 200; i++ {
+		name := fmt.Sprintf("member%03d", i)
+		r.Changes = append(r.Changes, Change{GameName: name, UserID: "123456789012345678", RoleID: "987654321098765432", Rank: idleclans.RankMember, InClan: true, Add: true})
+		r.NotInGuild = append(r.NotInGuild, name)
+	}
+	r.Failed = r.Changes[150:151]
+
+	got := FormatReport(r)
+	if n := len(got); n > maxReportLen {
+		t.Errorf("report is %d bytes, over Discord's %d", n, maxReportLen)
+	}
+	for _, want := range []string{"Made 199 of 200", "member150", "❌", "more\nNot in this server: member000", "more"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report lacks %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatReport(t *testing.T) {
+	change := Change{GameName: "guildan", UserID: "2", RoleID: "9", Rank: idleclans.RankOfficer, InClan: true, Add: true}
+
+	dry := FormatReport(Report{DryRun: true, Changes: []Change{change}, NotInGuild: []string{"stranger"}})
+	for _, want := range []string{"Dry run: 1 role change", "add <@&9> to guildan (<@2>, Officer)", "Not in this server: stranger"} {
+		if !strings.Contains(dry, want) {
+			t.Errorf("dry-run report lacks %q:\n%s", want, dry)
+		}
+	}
+
+	applied := FormatReport(Report{Changes: []Change{change}, Failed: []Change{change}})
+	if !strings.Contains(applied, "Made 0 of 1") || !strings.Contains(applied, "❌") {
+		t.Errorf("report doesn't flag the failed change:\n%s", applied)
+	}
+
+	if got := FormatReport(Report{}); !strings.HasPrefix(got, "✅") {
+		t.Errorf("empty report = %q", got)
+	}
+}